@@ -0,0 +1,11 @@
+package bags
+
+type Bag[T any] interface {
+	Add(T) error
+	TryTake() (T, error)
+}
+
+const (
+	EmptyBagError      = "Bag is already empty."
+	BagNilPointerError = "The bag pointer is nil."
+)
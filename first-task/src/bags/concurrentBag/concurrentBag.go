@@ -0,0 +1,112 @@
+package concurrentBag
+
+import (
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"src/bags"
+	"src/stacks/traiberStack"
+	"sync"
+	"sync/atomic"
+)
+
+// The bag keeps one Traiber stack per processor as a local buffer.
+// A handle is bound to one buffer, it adds to and takes from that buffer, and only when it is empty
+// it steals from the buffers of the others. The bag methods themselves borrow a handle from a sync.Pool,
+// which caches it per processor, so goroutines running on one processor mostly share one buffer.
+// Unlike sync.Pool, elements are never evicted by the GC, only the cached handles are.
+
+type Bag[T any] struct {
+	locals  []*traiberStack.Stack[T]
+	next    atomic.Uint64 // Round-robin counter for binding handles to buffers.
+	handles sync.Pool     // Handles for the bag methods, cached per processor.
+}
+
+// Local is a handle of one worker, bound to its own buffer of the bag, not to a processor.
+type Local[T any] struct {
+	bag  *Bag[T]
+	home int
+}
+
+func FreshConcurrentBag[T any]() *Bag[T] {
+	// New bag instance with a local buffer for every processor.
+	bag := &Bag[T]{locals: make([]*traiberStack.Stack[T], runtime.GOMAXPROCS(0))}
+	for i := range bag.locals {
+		bag.locals[i] = traiberStack.FreshTraiberStack[T]()
+	}
+	bag.handles.New = func() any { return bag.Local() }
+	return bag
+}
+
+func (bag *Bag[T]) Local() *Local[T] {
+	// Handles are spread over the buffers evenly, a worker should keep its handle for its whole life.
+	if bag == nil {
+		return nil
+	}
+	return &Local[T]{bag: bag, home: int(bag.next.Add(1) % uint64(len(bag.locals)))}
+}
+
+func (bag *Bag[T]) Add(value T) error {
+	// Without an own handle the one cached for the current processor is used.
+	if bag == nil {
+		return errors.New(bags.BagNilPointerError)
+	}
+	local := bag.handles.Get().(*Local[T])
+	defer bag.handles.Put(local)
+	return local.Add(value)
+}
+
+func (bag *Bag[T]) TryTake() (T, error) {
+	if bag == nil {
+		return *(new(T)), errors.New(bags.BagNilPointerError)
+	}
+	local := bag.handles.Get().(*Local[T])
+	defer bag.handles.Put(local)
+	return local.TryTake()
+}
+
+func (bag *Bag[T]) Len() (int, error) {
+	// Exact when there are no concurrent operations.
+	if bag == nil {
+		return 0, errors.New(bags.BagNilPointerError)
+	}
+	size := 0
+	for _, local := range bag.locals {
+		localSize, _ := local.Len()
+		size += localSize
+	}
+	return size, nil
+}
+
+func (local *Local[T]) Add(value T) error {
+	if local == nil {
+		return errors.New(bags.BagNilPointerError)
+	}
+	return local.bag.locals[local.home].Push(value)
+}
+
+func (local *Local[T]) TryTake() (T, error) {
+	if local == nil {
+		return *(new(T)), errors.New(bags.BagNilPointerError)
+	}
+	return local.bag.take(local.home)
+}
+
+func (bag *Bag[T]) take(home int) (T, error) {
+	// First the own buffer, then the others starting with a random victim.
+	if value, err := bag.locals[home].Pop(); err == nil {
+		return value, nil
+	}
+	amount := len(bag.locals)
+	start := rand.IntN(amount)
+	for i := 0; i < amount; i++ {
+		victim := (start + i) % amount
+		if victim == home {
+			continue
+		}
+		if value, err := bag.locals[victim].Pop(); err == nil {
+			return value, nil
+		}
+	}
+	return *(new(T)), errors.New(bags.EmptyBagError)
+}
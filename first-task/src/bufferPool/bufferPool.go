@@ -0,0 +1,131 @@
+package bufferPool
+
+import (
+	"math/bits"
+	"src/counters"
+	"src/counters/shardedCounter"
+	"src/stacks/traiberStack"
+	"sync/atomic"
+)
+
+// Pool of byte buffers with a Traiber stack as the free list of every size class.
+// Sizes are powers of two from MinBufferSize to MaxBufferSize, a request is served by the
+// smallest class that fits it. Unlike sync.Pool, free buffers are ordinary references held
+// by the stacks, so they survive garbage collections, and every class keeps at most a fixed
+// amount of them.
+//
+// Reuse is free of ABA: every Put wraps the buffer in a new cell, and a cell can't be
+// collected and allocated again while some goroutine still holds it for a CAS on the top.
+//
+// A nil pool keeps nothing: Get always allocates, Put drops the buffer and Stats are zero.
+
+const (
+	MinBufferSize = 1 << 6
+	MaxBufferSize = 1 << 20
+)
+
+type sizeClass struct {
+	free   *traiberStack.Stack[[]byte]
+	amount atomic.Int64 // The amount of buffers in free, may exceed the limit for a moment.
+}
+
+type Stats struct {
+	Hits    int64 // Gets served from a free list.
+	Misses  int64 // Gets that had to allocate.
+	Puts    int64 // Buffers kept for reuse.
+	Dropped int64 // Buffers rejected because the class was full or they did not fit any class.
+}
+
+type Pool struct {
+	classes []sizeClass
+	limit   int64 // The maximum amount of free buffers in a class.
+	hits    counters.Counter
+	misses  counters.Counter
+	puts    counters.Counter
+	dropped counters.Counter
+}
+
+func FreshBufferPool(buffersPerClass int) *Pool {
+	// New pool instance that keeps at most buffersPerClass free buffers of every size.
+	pool := &Pool{
+		classes: make([]sizeClass, classIndex(MaxBufferSize)+1),
+		limit:   int64(max(buffersPerClass, 0)),
+		hits:    shardedCounter.FreshShardedCounter(),
+		misses:  shardedCounter.FreshShardedCounter(),
+		puts:    shardedCounter.FreshShardedCounter(),
+		dropped: shardedCounter.FreshShardedCounter(),
+	}
+	for i := range pool.classes {
+		pool.classes[i].free = traiberStack.FreshTraiberStack[[]byte]()
+	}
+	return pool
+}
+
+func classIndex(size int) int {
+	// The index of the smallest class that fits size bytes.
+	if size <= MinBufferSize {
+		return 0
+	}
+	return bits.Len(uint(size-1)) - bits.Len(uint(MinBufferSize-1))
+}
+
+func (pool *Pool) Get(size int) []byte {
+	// Buffer of length size. Its capacity is the size of the class and its content is arbitrary.
+	if pool == nil {
+		return make([]byte, max(size, 0))
+	}
+	if size > MaxBufferSize {
+		pool.misses.Add(1)
+		return make([]byte, size)
+	}
+	size = max(size, 0)
+	class := &pool.classes[classIndex(size)]
+	if buffer, err := class.free.Pop(); err == nil {
+		class.amount.Add(-1)
+		pool.hits.Add(1)
+		return buffer[:size]
+	}
+	pool.misses.Add(1)
+	return make([]byte, size, MinBufferSize<<classIndex(size))
+}
+
+func (pool *Pool) Put(buffer []byte) {
+	// Returns the buffer for reuse, it must not be used by the caller afterwards.
+	if pool == nil {
+		return
+	}
+	size := cap(buffer)
+	if size < MinBufferSize || size > MaxBufferSize || size&(size-1) != 0 {
+		pool.dropped.Add(1)
+		return
+	}
+	class := &pool.classes[classIndex(size)]
+	if class.amount.Add(1) > pool.limit {
+		class.amount.Add(-1)
+		pool.dropped.Add(1)
+		return
+	}
+	class.free.Push(buffer[:size])
+	pool.puts.Add(1)
+}
+
+func (pool *Pool) Stats() Stats {
+	// The counters are read one by one, so under load they may be slightly inconsistent.
+	if pool == nil {
+		return Stats{}
+	}
+	return Stats{
+		Hits:    pool.hits.Load(),
+		Misses:  pool.misses.Load(),
+		Puts:    pool.puts.Load(),
+		Dropped: pool.dropped.Load(),
+	}
+}
+
+func (stats Stats) HitRate() float64 {
+	// The share of Gets served without allocation.
+	if stats.Hits+stats.Misses == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(stats.Hits+stats.Misses)
+}
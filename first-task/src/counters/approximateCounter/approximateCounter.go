@@ -0,0 +1,64 @@
+package approximateCounter
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"src/counters"
+	"sync/atomic"
+)
+
+// Additions are accumulated in local shards and moved to the global value
+// once a shard reaches the threshold, so Load reads a single word.
+// When there are no concurrent additions, the global value differs from the exact one
+// by less than threshold in every shard, that is by at most ErrorBound.
+
+type shard struct {
+	value atomic.Int64
+	_     counters.CacheLinePad
+}
+
+type Counter struct {
+	global    atomic.Int64
+	_         counters.CacheLinePad
+	shards    []shard
+	threshold int64
+}
+
+func FreshApproximateCounter(threshold int64) *Counter {
+	// New counter instance with a shard for every processor.
+	return &Counter{shards: make([]shard, runtime.GOMAXPROCS(0)), threshold: max(threshold, 1)}
+}
+
+func (counter *Counter) Add(delta int64) {
+	if counter == nil {
+		return
+	}
+	local := &counter.shards[rand.IntN(len(counter.shards))].value
+	if value := local.Add(delta); value >= counter.threshold || value <= -counter.threshold {
+		counter.global.Add(local.Swap(0))
+	}
+}
+
+func (counter *Counter) Load() int64 {
+	if counter == nil {
+		return 0
+	}
+	return counter.global.Load()
+}
+
+func (counter *Counter) Reset() {
+	if counter == nil {
+		return
+	}
+	for i := range counter.shards {
+		counter.shards[i].value.Store(0)
+	}
+	counter.global.Store(0)
+}
+
+func (counter *Counter) ErrorBound() int64 {
+	if counter == nil {
+		return 0
+	}
+	return int64(len(counter.shards)) * (counter.threshold - 1)
+}
@@ -0,0 +1,175 @@
+package combiningTreeCounter
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+)
+
+// Software combining tree from "The Art of Multiprocessor Programming".
+// Goroutines enter at the leaves and climb towards the root. When two of them meet in a node,
+// the second one hands its value to the first one and waits, so only one of them goes further up
+// and the root, which holds the value, is touched by much fewer goroutines.
+// In the book every leaf is shared by exactly two threads. Goroutines have no identity,
+// so here any number of them may enter a leaf: a goroutine that finds the node busy with a pair
+// simply waits until the pair leaves it.
+
+type status int
+
+const (
+	idle   status = 0 // Nobody is in the node.
+	first  status = 1 // One goroutine has passed the node and may take the value of a second one.
+	second status = 2 // The second goroutine has left its value and waits for the result.
+	result status = 3 // The result for the second goroutine is ready.
+	root   status = 4
+)
+
+type node struct {
+	mutex       sync.Mutex
+	changed     *sync.Cond
+	locked      bool // Forbids the first goroutine to combine before the second one has left its value.
+	status      status
+	firstValue  int64
+	secondValue int64
+	result      int64
+	parent      *node
+}
+
+type Counter struct {
+	root   *node
+	leaves []*node
+}
+
+func FreshCombiningTreeCounter() *Counter {
+	// New counter instance with a leaf for every two processors.
+	leavesAmount := 1
+	for 2*leavesAmount < runtime.GOMAXPROCS(0) {
+		leavesAmount *= 2
+	}
+	nodes := make([]*node, 2*leavesAmount-1)
+	for i := range nodes {
+		nodes[i] = &node{status: idle}
+		nodes[i].changed = sync.NewCond(&nodes[i].mutex)
+		if i > 0 {
+			nodes[i].parent = nodes[(i-1)/2]
+		}
+	}
+	nodes[0].status = root
+	return &Counter{root: nodes[0], leaves: nodes[leavesAmount-1:]}
+}
+
+func (counter *Counter) Add(delta int64) {
+	if counter == nil {
+		return
+	}
+	leaf := counter.leaves[rand.IntN(len(counter.leaves))]
+
+	// Precombining: climb while being the first goroutine in the node.
+	current := leaf
+	for current.precombine() {
+		current = current.parent
+	}
+	stop := current
+
+	// Combining: collect the values left by the second goroutines on the way up.
+	var path []*node
+	combined := delta
+	for current = leaf; current != stop; current = current.parent {
+		combined = current.combine(combined)
+		path = append(path, current)
+	}
+
+	// Operation: either apply the sum at the root, or leave it to the first goroutine of the stop node.
+	prior := stop.operate(combined)
+
+	// Distribution: release the second goroutines on the way back.
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].distribute(prior)
+	}
+}
+
+func (counter *Counter) Load() int64 {
+	if counter == nil {
+		return 0
+	}
+	counter.root.mutex.Lock()
+	defer counter.root.mutex.Unlock()
+	return counter.root.result
+}
+
+func (counter *Counter) Reset() {
+	if counter == nil {
+		return
+	}
+	counter.root.mutex.Lock()
+	defer counter.root.mutex.Unlock()
+	counter.root.result = 0
+}
+
+func (current *node) precombine() bool {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	for current.locked || current.status == second || current.status == result {
+		current.changed.Wait()
+	}
+	switch current.status {
+	case idle:
+		current.status = first
+		return true
+	case first:
+		current.locked = true
+		current.status = second
+		return false
+	default:
+		return false
+	}
+}
+
+func (current *node) combine(combined int64) int64 {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	for current.locked {
+		current.changed.Wait()
+	}
+	current.locked = true
+	current.firstValue = combined
+	if current.status == second {
+		return current.firstValue + current.secondValue
+	}
+	return current.firstValue
+}
+
+func (current *node) operate(combined int64) int64 {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	if current.status == root {
+		prior := current.result
+		current.result += combined
+		return prior
+	}
+
+	// The second goroutine: leave the value and wait until the first one brings the result.
+	current.secondValue = combined
+	current.locked = false
+	current.changed.Broadcast()
+	for current.status != result {
+		current.changed.Wait()
+	}
+	current.locked = false
+	current.status = idle
+	current.changed.Broadcast()
+	return current.result
+}
+
+func (current *node) distribute(prior int64) {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	if current.status == first {
+		current.status = idle
+		current.locked = false
+	} else {
+		current.result = prior + current.firstValue
+		current.status = result
+	}
+	current.changed.Broadcast()
+}
@@ -0,0 +1,11 @@
+package counters
+
+// The methods report no errors, so a nil counter ignores additions and resets and always loads zero.
+type Counter interface {
+	Add(int64)
+	Load() int64
+	Reset()
+}
+
+// Padding that keeps neighbouring shards in different cache lines.
+type CacheLinePad [64]byte
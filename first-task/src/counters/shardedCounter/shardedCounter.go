@@ -0,0 +1,53 @@
+package shardedCounter
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"src/counters"
+	"sync/atomic"
+)
+
+// The value is split into shards, each goroutine adds to a random one,
+// so concurrent additions rarely touch the same cache line. Load sums all the shards.
+
+type shard struct {
+	value atomic.Int64
+	_     counters.CacheLinePad
+}
+
+type Counter struct {
+	shards []shard
+}
+
+func FreshShardedCounter() *Counter {
+	// New counter instance with a shard for every processor.
+	return &Counter{shards: make([]shard, runtime.GOMAXPROCS(0))}
+}
+
+func (counter *Counter) Add(delta int64) {
+	if counter == nil {
+		return
+	}
+	counter.shards[rand.IntN(len(counter.shards))].value.Add(delta)
+}
+
+func (counter *Counter) Load() int64 {
+	// Exact when there are no concurrent additions.
+	if counter == nil {
+		return 0
+	}
+	sum := int64(0)
+	for i := range counter.shards {
+		sum += counter.shards[i].value.Load()
+	}
+	return sum
+}
+
+func (counter *Counter) Reset() {
+	if counter == nil {
+		return
+	}
+	for i := range counter.shards {
+		counter.shards[i].value.Store(0)
+	}
+}
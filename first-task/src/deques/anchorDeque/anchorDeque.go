@@ -0,0 +1,240 @@
+package anchorDeque
+
+import (
+	"errors"
+	"src/deques"
+	"sync/atomic"
+)
+
+// Lock-free deque by Maged Michael. Both ends and the status of the deque live in one immutable anchor,
+// which is replaced with a single CAS. A push links the new node from one side only and marks
+// the anchor as unstable, any operation that meets an unstable anchor completes the second link first.
+// Anchors are never reused, so comparing their addresses is free from the ABA problem.
+// A pop unlinks the new end from the popped node and takes the value out of it, otherwise
+// every node would keep all the nodes popped before it reachable and the deque would only grow.
+
+type status int
+
+const (
+	stable    status = 0
+	rightPush status = 1
+	leftPush  status = 2
+)
+
+type node[T any] struct {
+	value atomic.Pointer[T] // Nil once the node is popped.
+	left  atomic.Pointer[node[T]]
+	right atomic.Pointer[node[T]]
+}
+
+type anchor[T any] struct {
+	left   *node[T]
+	right  *node[T]
+	status status
+}
+
+type Deque[T any] struct {
+	anchor atomic.Pointer[anchor[T]]
+	size   atomic.Int64 // Updated after the anchor, so it may lag behind concurrent operations.
+}
+
+func FreshAnchorDeque[T any]() *Deque[T] {
+	// New deque instance.
+	deque := &Deque[T]{}
+	deque.anchor.Store(&anchor[T]{})
+	return deque
+}
+
+func (deque *Deque[T]) PushFront(value T) error {
+	if deque == nil {
+		return errors.New(deques.DequeNilPointerError)
+	}
+	fresh := &node[T]{}
+	fresh.value.Store(&value)
+	for {
+		current := deque.anchor.Load()
+		if current.left == nil {
+			if deque.anchor.CompareAndSwap(current, &anchor[T]{left: fresh, right: fresh, status: stable}) {
+				break
+			}
+		} else if current.status == stable {
+			fresh.right.Store(current.left)
+			next := &anchor[T]{left: fresh, right: current.right, status: leftPush}
+			if deque.anchor.CompareAndSwap(current, next) {
+				deque.stabilizeLeft(next)
+				break
+			}
+		} else {
+			deque.stabilize(current)
+		}
+	}
+	deque.size.Add(1)
+	return nil
+}
+
+func (deque *Deque[T]) PushBack(value T) error {
+	if deque == nil {
+		return errors.New(deques.DequeNilPointerError)
+	}
+	fresh := &node[T]{}
+	fresh.value.Store(&value)
+	for {
+		current := deque.anchor.Load()
+		if current.right == nil {
+			if deque.anchor.CompareAndSwap(current, &anchor[T]{left: fresh, right: fresh, status: stable}) {
+				break
+			}
+		} else if current.status == stable {
+			fresh.left.Store(current.right)
+			next := &anchor[T]{left: current.left, right: fresh, status: rightPush}
+			if deque.anchor.CompareAndSwap(current, next) {
+				deque.stabilizeRight(next)
+				break
+			}
+		} else {
+			deque.stabilize(current)
+		}
+	}
+	deque.size.Add(1)
+	return nil
+}
+
+func (deque *Deque[T]) PopFront() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	for {
+		current := deque.anchor.Load()
+		if current.left == nil {
+			return *(new(T)), errors.New(deques.EmptyDequeError)
+		}
+		if current.left == current.right {
+			if deque.anchor.CompareAndSwap(current, &anchor[T]{}) {
+				return deque.popped(current.left)
+			}
+		} else if current.status == stable {
+			next := &anchor[T]{left: current.left.right.Load(), right: current.right, status: stable}
+			if deque.anchor.CompareAndSwap(current, next) {
+				// A failed CAS means that a concurrent push has already linked a fresh node instead.
+				next.left.left.CompareAndSwap(current.left, nil)
+				return deque.popped(current.left)
+			}
+		} else {
+			deque.stabilize(current)
+		}
+	}
+}
+
+func (deque *Deque[T]) PopBack() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	for {
+		current := deque.anchor.Load()
+		if current.right == nil {
+			return *(new(T)), errors.New(deques.EmptyDequeError)
+		}
+		if current.left == current.right {
+			if deque.anchor.CompareAndSwap(current, &anchor[T]{}) {
+				return deque.popped(current.right)
+			}
+		} else if current.status == stable {
+			next := &anchor[T]{left: current.left, right: current.right.left.Load(), status: stable}
+			if deque.anchor.CompareAndSwap(current, next) {
+				next.right.right.CompareAndSwap(current.right, nil)
+				return deque.popped(current.right)
+			}
+		} else {
+			deque.stabilize(current)
+		}
+	}
+}
+
+func (deque *Deque[T]) PeekFront() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	for {
+		current := deque.anchor.Load()
+		if current.left == nil {
+			return *(new(T)), errors.New(deques.EmptyDequeError)
+		}
+		// The node may be popped in the meantime, then look at the next anchor.
+		if value := current.left.value.Load(); value != nil {
+			return *value, nil
+		}
+	}
+}
+
+func (deque *Deque[T]) PeekBack() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	for {
+		current := deque.anchor.Load()
+		if current.right == nil {
+			return *(new(T)), errors.New(deques.EmptyDequeError)
+		}
+		if value := current.right.value.Load(); value != nil {
+			return *value, nil
+		}
+	}
+}
+
+func (deque *Deque[T]) Len() (int, error) {
+	// Exact when there are no concurrent operations.
+	if deque == nil {
+		return 0, errors.New(deques.DequeNilPointerError)
+	}
+	return int(max(deque.size.Load(), 0)), nil
+}
+
+func (deque *Deque[T]) popped(removed *node[T]) (T, error) {
+	// Only the winner of the anchor CAS gets here, so the value is still in place.
+	deque.size.Add(-1)
+	return *removed.value.Swap(nil), nil
+}
+
+func (deque *Deque[T]) stabilize(current *anchor[T]) {
+	if current.status == rightPush {
+		deque.stabilizeRight(current)
+	} else {
+		deque.stabilizeLeft(current)
+	}
+}
+
+func (deque *Deque[T]) stabilizeRight(current *anchor[T]) {
+	// Link the previous right-most node to the freshly pushed one, then mark the anchor as stable.
+	prev := current.right.left.Load()
+	if deque.anchor.Load() != current {
+		return
+	}
+	prevNext := prev.right.Load()
+	if prevNext != current.right {
+		if deque.anchor.Load() != current {
+			return
+		}
+		if !prev.right.CompareAndSwap(prevNext, current.right) {
+			return
+		}
+	}
+	deque.anchor.CompareAndSwap(current, &anchor[T]{left: current.left, right: current.right, status: stable})
+}
+
+func (deque *Deque[T]) stabilizeLeft(current *anchor[T]) {
+	// Mirror image of stabilizeRight.
+	next := current.left.right.Load()
+	if deque.anchor.Load() != current {
+		return
+	}
+	nextPrev := next.left.Load()
+	if nextPrev != current.left {
+		if deque.anchor.Load() != current {
+			return
+		}
+		if !next.left.CompareAndSwap(nextPrev, current.left) {
+			return
+		}
+	}
+	deque.anchor.CompareAndSwap(current, &anchor[T]{left: current.left, right: current.right, status: stable})
+}
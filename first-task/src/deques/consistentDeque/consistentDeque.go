@@ -0,0 +1,116 @@
+package consistentDeque
+
+import (
+	"errors"
+	"src/deques"
+)
+
+type cell[T any] struct {
+	value T
+	prev  *cell[T]
+	next  *cell[T]
+}
+
+type Deque[T any] struct {
+	front *cell[T]
+	back  *cell[T]
+	size  int
+}
+
+func FreshConsistentDeque[T any]() *Deque[T] {
+	// New deque instance.
+	return &Deque[T]{}
+}
+
+func (deque *Deque[T]) PushFront(value T) error {
+	if deque == nil {
+		return errors.New(deques.DequeNilPointerError)
+	}
+	fresh := &cell[T]{value: value, next: deque.front}
+	if deque.front == nil {
+		deque.back = fresh
+	} else {
+		deque.front.prev = fresh
+	}
+	deque.front = fresh
+	deque.size++
+	return nil
+}
+
+func (deque *Deque[T]) PushBack(value T) error {
+	if deque == nil {
+		return errors.New(deques.DequeNilPointerError)
+	}
+	fresh := &cell[T]{value: value, prev: deque.back}
+	if deque.back == nil {
+		deque.front = fresh
+	} else {
+		deque.back.next = fresh
+	}
+	deque.back = fresh
+	deque.size++
+	return nil
+}
+
+func (deque *Deque[T]) PopFront() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	if deque.front == nil {
+		return *(new(T)), errors.New(deques.EmptyDequeError)
+	}
+	value := deque.front.value
+	deque.front = deque.front.next
+	if deque.front == nil {
+		deque.back = nil
+	} else {
+		deque.front.prev = nil
+	}
+	deque.size--
+	return value, nil
+}
+
+func (deque *Deque[T]) PopBack() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	if deque.back == nil {
+		return *(new(T)), errors.New(deques.EmptyDequeError)
+	}
+	value := deque.back.value
+	deque.back = deque.back.prev
+	if deque.back == nil {
+		deque.front = nil
+	} else {
+		deque.back.next = nil
+	}
+	deque.size--
+	return value, nil
+}
+
+func (deque *Deque[T]) PeekFront() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	if deque.front == nil {
+		return *(new(T)), errors.New(deques.EmptyDequeError)
+	}
+	return deque.front.value, nil
+}
+
+func (deque *Deque[T]) PeekBack() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	if deque.back == nil {
+		return *(new(T)), errors.New(deques.EmptyDequeError)
+	}
+	return deque.back.value, nil
+}
+
+func (deque *Deque[T]) Len() (int, error) {
+	if deque == nil {
+		return 0, errors.New(deques.DequeNilPointerError)
+	}
+	return deque.size, nil
+}
@@ -0,0 +1,16 @@
+package deques
+
+type Deque[T any] interface {
+	PushFront(T) error
+	PushBack(T) error
+	PopFront() (T, error)
+	PopBack() (T, error)
+	PeekFront() (T, error)
+	PeekBack() (T, error)
+	Len() (int, error)
+}
+
+const (
+	EmptyDequeError      = "Deque is already empty."
+	DequeNilPointerError = "The deque pointer is nil."
+)
@@ -0,0 +1,160 @@
+package twoLockDeque
+
+import (
+	"errors"
+	"src/deques"
+	"sync"
+)
+
+// The deque is split into two halves, each guarded by its own lock.
+// The front half keeps the front-most element at its end, the back half keeps the back-most one,
+// so the deque is the reversed front half followed by the back half.
+// An operation at one end needs only its own lock, both locks are taken (front first)
+// only when its half is empty and elements have to be moved over from the other half.
+
+type Deque[T any] struct {
+	front      []T
+	back       []T
+	frontMutex sync.Mutex
+	backMutex  sync.Mutex
+}
+
+func FreshTwoLockDeque[T any]() *Deque[T] {
+	// New deque instance.
+	return &Deque[T]{}
+}
+
+func (deque *Deque[T]) PushFront(value T) error {
+	if deque == nil {
+		return errors.New(deques.DequeNilPointerError)
+	}
+	deque.frontMutex.Lock()
+	defer deque.frontMutex.Unlock()
+	deque.front = append(deque.front, value)
+	return nil
+}
+
+func (deque *Deque[T]) PushBack(value T) error {
+	if deque == nil {
+		return errors.New(deques.DequeNilPointerError)
+	}
+	deque.backMutex.Lock()
+	defer deque.backMutex.Unlock()
+	deque.back = append(deque.back, value)
+	return nil
+}
+
+func (deque *Deque[T]) PopFront() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	deque.frontMutex.Lock()
+	defer deque.frontMutex.Unlock()
+	if len(deque.front) == 0 {
+		deque.backMutex.Lock()
+		deque.front, deque.back = steal(deque.back)
+		deque.backMutex.Unlock()
+	}
+	return pop(&deque.front)
+}
+
+func (deque *Deque[T]) PopBack() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	deque.backMutex.Lock()
+	if len(deque.back) > 0 {
+		defer deque.backMutex.Unlock()
+		return pop(&deque.back)
+	}
+	// Respect the lock order: release the back lock and take both of them starting with the front one.
+	deque.backMutex.Unlock()
+	deque.lockBoth()
+	defer deque.unlockBoth()
+	if len(deque.back) == 0 {
+		deque.back, deque.front = steal(deque.front)
+	}
+	return pop(&deque.back)
+}
+
+func (deque *Deque[T]) PeekFront() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	deque.frontMutex.Lock()
+	defer deque.frontMutex.Unlock()
+	if len(deque.front) > 0 {
+		return deque.front[len(deque.front)-1], nil
+	}
+	// The front-most element is the bottom of the back half.
+	deque.backMutex.Lock()
+	defer deque.backMutex.Unlock()
+	if len(deque.back) > 0 {
+		return deque.back[0], nil
+	}
+	return *(new(T)), errors.New(deques.EmptyDequeError)
+}
+
+func (deque *Deque[T]) PeekBack() (T, error) {
+	if deque == nil {
+		return *(new(T)), errors.New(deques.DequeNilPointerError)
+	}
+	deque.backMutex.Lock()
+	if len(deque.back) > 0 {
+		defer deque.backMutex.Unlock()
+		return deque.back[len(deque.back)-1], nil
+	}
+	deque.backMutex.Unlock()
+	deque.lockBoth()
+	defer deque.unlockBoth()
+	if len(deque.back) > 0 {
+		return deque.back[len(deque.back)-1], nil
+	}
+	if len(deque.front) > 0 {
+		return deque.front[0], nil
+	}
+	return *(new(T)), errors.New(deques.EmptyDequeError)
+}
+
+func (deque *Deque[T]) Len() (int, error) {
+	if deque == nil {
+		return 0, errors.New(deques.DequeNilPointerError)
+	}
+	deque.lockBoth()
+	defer deque.unlockBoth()
+	return len(deque.front) + len(deque.back), nil
+}
+
+func (deque *Deque[T]) lockBoth() {
+	deque.frontMutex.Lock()
+	deque.backMutex.Lock()
+}
+
+func (deque *Deque[T]) unlockBoth() {
+	deque.backMutex.Unlock()
+	deque.frontMutex.Unlock()
+}
+
+func steal[T any](other []T) ([]T, []T) {
+	// Move the half of the other part that is closer to the empty end,
+	// so that alternating operations at both ends do not shuffle all elements every time.
+	amount := (len(other) + 1) / 2
+	taken := make([]T, amount)
+	for i := 0; i < amount; i++ {
+		taken[i] = other[amount-1-i]
+	}
+	rest := make([]T, len(other)-amount)
+	copy(rest, other[amount:])
+	return taken, rest
+}
+
+func pop[T any](part *[]T) (T, error) {
+	if len(*part) == 0 {
+		return *(new(T)), errors.New(deques.EmptyDequeError)
+	}
+	last := len(*part) - 1
+	value := (*part)[last]
+	(*part)[last] = *(new(T))
+	*part = (*part)[:last]
+	return value, nil
+}
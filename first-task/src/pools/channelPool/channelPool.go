@@ -0,0 +1,90 @@
+package channelPool
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"src/pools"
+	"sync"
+)
+
+type Pool struct {
+	tasks    chan func() // The only queue, shared by all workers.
+	inflight sync.WaitGroup
+	workers  sync.WaitGroup
+
+	closed     bool
+	closeMutex sync.RWMutex // Forbids sending into the channel after it was closed.
+	closeOnce  sync.Once
+	exited     chan struct{}
+}
+
+func FreshChannelPool(workersAmount, capacity int) *Pool {
+	// New pool instance, a non-positive amount means one worker per processor.
+	// Submit blocks while the channel holds capacity tasks.
+	if workersAmount <= 0 {
+		workersAmount = runtime.GOMAXPROCS(0)
+	}
+	pool := &Pool{
+		tasks:  make(chan func(), capacity),
+		exited: make(chan struct{}),
+	}
+	pool.workers.Add(workersAmount)
+	for i := 0; i < workersAmount; i++ {
+		go pool.work()
+	}
+	return pool
+}
+
+func (pool *Pool) Submit(function func()) error {
+	if pool == nil {
+		return errors.New(pools.PoolNilPointerError)
+	}
+	pool.closeMutex.RLock()
+	defer pool.closeMutex.RUnlock()
+	if pool.closed {
+		return errors.New(pools.PoolClosedError)
+	}
+	pool.inflight.Add(1)
+	pool.tasks <- function
+	return nil
+}
+
+func (pool *Pool) Wait() {
+	if pool == nil {
+		return
+	}
+	pool.inflight.Wait()
+}
+
+func (pool *Pool) Shutdown(ctx context.Context) error {
+	if pool == nil {
+		return errors.New(pools.PoolNilPointerError)
+	}
+	pool.closeOnce.Do(func() {
+		pool.closeMutex.Lock()
+		pool.closed = true
+		close(pool.tasks)
+		pool.closeMutex.Unlock()
+
+		go func() {
+			pool.workers.Wait()
+			close(pool.exited)
+		}()
+	})
+
+	select {
+	case <-pool.exited:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (pool *Pool) work() {
+	defer pool.workers.Done()
+	for function := range pool.tasks {
+		function()
+		pool.inflight.Done()
+	}
+}
@@ -0,0 +1,14 @@
+package pools
+
+import "context"
+
+type Pool interface {
+	Submit(func()) error
+	Wait()
+	Shutdown(context.Context) error
+}
+
+const (
+	PoolClosedError     = "The pool is already shut down."
+	PoolNilPointerError = "The pool pointer is nil."
+)
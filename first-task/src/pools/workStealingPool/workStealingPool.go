@@ -0,0 +1,191 @@
+package workStealingPool
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"runtime"
+	"src/deques/anchorDeque"
+	"src/pools"
+	"sync"
+	"sync/atomic"
+)
+
+// Every worker owns a lock-free anchor deque: it pushes and pops its own tasks at the back, LIFO,
+// while thieves take the oldest tasks from the front. Neither side ever blocks the other:
+// whoever loses the CAS of the anchor retries on the new one.
+// A task learns the worker that runs it from its argument and submits its children through that worker,
+// only the submissions from outside the pool are spread round-robin.
+
+type task func(*Worker)
+
+type Pool struct {
+	deques   []*anchorDeque.Deque[task]
+	next     atomic.Uint64  // Round-robin counter for choosing the deque of an external task.
+	queued   atomic.Int64   // Tasks that are pushed, but not yet taken by any worker.
+	idle     atomic.Int64   // Workers that are about to sleep or already sleeping.
+	inflight sync.WaitGroup // Tasks that are submitted, but not yet finished.
+	workers  sync.WaitGroup // Running worker goroutines.
+
+	closed     atomic.Bool
+	closeMutex sync.RWMutex // Orders Submit calls with respect to Shutdown.
+	closeOnce  sync.Once
+	exited     chan struct{} // Closed when all workers have exited.
+
+	sleepMutex sync.Mutex
+	wakeUp     *sync.Cond
+}
+
+type Worker struct {
+	pool  *Pool
+	index int // The own deque of the worker.
+}
+
+func FreshWorkStealingPool(workersAmount int) *Pool {
+	// New pool instance, a non-positive amount means one worker per processor.
+	if workersAmount <= 0 {
+		workersAmount = runtime.GOMAXPROCS(0)
+	}
+	pool := &Pool{
+		deques: make([]*anchorDeque.Deque[task], workersAmount),
+		exited: make(chan struct{}),
+	}
+	pool.wakeUp = sync.NewCond(&pool.sleepMutex)
+	for i := range pool.deques {
+		pool.deques[i] = anchorDeque.FreshAnchorDeque[task]()
+	}
+	pool.workers.Add(workersAmount)
+	for i := 0; i < workersAmount; i++ {
+		go pool.work(&Worker{pool: pool, index: i})
+	}
+	return pool
+}
+
+func (pool *Pool) Submit(function func()) error {
+	return pool.Spawn(func(*Worker) { function() })
+}
+
+func (pool *Pool) Spawn(function func(*Worker)) error {
+	// Submit a task from outside the pool, the task receives the worker that runs it.
+	if pool == nil {
+		return errors.New(pools.PoolNilPointerError)
+	}
+	index := pool.next.Add(1) % uint64(len(pool.deques))
+	return pool.push(int(index), function)
+}
+
+func (worker *Worker) Submit(function func(*Worker)) error {
+	// Submit a child task into the own deque of the worker, it runs next on the same worker unless it is stolen.
+	if worker == nil {
+		return errors.New(pools.PoolNilPointerError)
+	}
+	return worker.pool.push(worker.index, function)
+}
+
+func (pool *Pool) push(index int, function task) error {
+	pool.closeMutex.RLock()
+	defer pool.closeMutex.RUnlock()
+	if pool.closed.Load() {
+		return errors.New(pools.PoolClosedError)
+	}
+
+	pool.inflight.Add(1)
+	pool.deques[index].PushBack(function)
+	pool.queued.Add(1)
+
+	// Wake up a sleeping worker only if there is one, so that the common path does not touch the sleep lock.
+	if pool.idle.Load() > 0 {
+		pool.sleepMutex.Lock()
+		pool.wakeUp.Signal()
+		pool.sleepMutex.Unlock()
+	}
+	return nil
+}
+
+func (pool *Pool) Wait() {
+	// Tasks submitted by other tasks are counted before their parent finishes,
+	// so the counter can not drop to zero while the fork-join computation is still running.
+	if pool == nil {
+		return
+	}
+	pool.inflight.Wait()
+}
+
+func (pool *Pool) Shutdown(ctx context.Context) error {
+	// Stop accepting new tasks, let the workers drain the already queued ones
+	// and wait for them to exit or for the context to be done.
+	if pool == nil {
+		return errors.New(pools.PoolNilPointerError)
+	}
+	pool.closeOnce.Do(func() {
+		pool.closeMutex.Lock()
+		pool.closed.Store(true)
+		pool.closeMutex.Unlock()
+
+		pool.sleepMutex.Lock()
+		pool.wakeUp.Broadcast()
+		pool.sleepMutex.Unlock()
+
+		go func() {
+			pool.workers.Wait()
+			close(pool.exited)
+		}()
+	})
+
+	select {
+	case <-pool.exited:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (pool *Pool) work(worker *Worker) {
+	defer pool.workers.Done()
+	for {
+		if function, ok := pool.take(worker.index); ok {
+			function(worker)
+			pool.inflight.Done()
+			continue
+		}
+
+		// Nothing to take or steal: fall asleep until a new task is submitted.
+		// The idle counter is raised before the queue is re-checked, and Submit raises
+		// the queue counter before it checks the idle one, so a wake-up can not be lost.
+		pool.sleepMutex.Lock()
+		pool.idle.Add(1)
+		for pool.queued.Load() <= 0 && !pool.closed.Load() {
+			pool.wakeUp.Wait()
+		}
+		pool.idle.Add(-1)
+		finished := pool.queued.Load() <= 0 && pool.closed.Load()
+		pool.sleepMutex.Unlock()
+		if finished {
+			return
+		}
+	}
+}
+
+func (pool *Pool) take(index int) (task, bool) {
+	// First look into the own deque, the most recently pushed task is the hottest in the cache.
+	if function, err := pool.deques[index].PopBack(); err == nil {
+		pool.queued.Add(-1)
+		return function, true
+	}
+
+	// Then try to steal from the other workers, starting with a random victim.
+	// The oldest task of a fork-join computation is the closest to the root, so it is usually the largest one.
+	amount := len(pool.deques)
+	start := rand.Intn(amount)
+	for i := 0; i < amount; i++ {
+		victim := (start + i) % amount
+		if victim == index {
+			continue
+		}
+		if function, err := pool.deques[victim].PopFront(); err == nil {
+			pool.queued.Add(-1)
+			return function, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,163 @@
+package multiQueue
+
+import (
+	"cmp"
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"src/queues"
+	"sync"
+	"sync/atomic"
+)
+
+// Relaxed priority queue by Rihani, Sanders and Dementiev. The elements are spread over c·P
+// sequential heaps, each protected by its own lock. Insert goes to a random heap, DeleteMin
+// looks at the tops of two random heaps and takes the smaller one. The removed element is not
+// necessarily the global minimum, but its rank is small on average, while the goroutines rarely
+// compete for the same lock.
+
+type item[T any, K cmp.Ordered] struct {
+	key   K
+	value T
+}
+
+type heap[T any, K cmp.Ordered] struct {
+	mutex sync.Mutex
+	items []item[T, K]
+	_     [64]byte // Keeps the locks of neighbouring heaps in different cache lines.
+}
+
+type MultiQueue[T any, K cmp.Ordered] struct {
+	heaps []heap[T, K]
+	size  atomic.Int64
+}
+
+func FreshMultiQueue[T any, K cmp.Ordered](factor int) *MultiQueue[T, K] {
+	// New queue instance with factor heaps for every processor.
+	return &MultiQueue[T, K]{heaps: make([]heap[T, K], max(factor, 1)*runtime.GOMAXPROCS(0))}
+}
+
+func (queue *MultiQueue[T, K]) Insert(key K, value T) error {
+	if queue == nil {
+		return errors.New(queues.QueueNilPointerError)
+	}
+	// Skip the heaps that are busy right now, there are plenty of others.
+	for {
+		current := &queue.heaps[rand.IntN(len(queue.heaps))]
+		if current.mutex.TryLock() {
+			current.push(item[T, K]{key: key, value: value})
+			current.mutex.Unlock()
+			queue.size.Add(1)
+			return nil
+		}
+	}
+}
+
+func (queue *MultiQueue[T, K]) DeleteMin() (K, T, error) {
+	if queue == nil {
+		return *(new(K)), *(new(T)), errors.New(queues.QueueNilPointerError)
+	}
+	for attempt := 0; attempt < len(queue.heaps) && queue.size.Load() > 0; attempt++ {
+		first, second := queue.pickTwo()
+		if found, removed := queue.deleteSmaller(first, second); found {
+			queue.size.Add(-1)
+			return removed.key, removed.value, nil
+		}
+	}
+
+	// The queue is almost empty, so random choices keep missing: look through all the heaps.
+	start := rand.IntN(len(queue.heaps))
+	for i := 0; i < len(queue.heaps); i++ {
+		current := &queue.heaps[(start+i)%len(queue.heaps)]
+		current.mutex.Lock()
+		if len(current.items) > 0 {
+			removed := current.pop()
+			current.mutex.Unlock()
+			queue.size.Add(-1)
+			return removed.key, removed.value, nil
+		}
+		current.mutex.Unlock()
+	}
+	return *(new(K)), *(new(T)), errors.New(queues.EmptyQueueError)
+}
+
+func (queue *MultiQueue[T, K]) Len() (int, error) {
+	// Exact when there are no concurrent operations.
+	if queue == nil {
+		return 0, errors.New(queues.QueueNilPointerError)
+	}
+	return int(max(queue.size.Load(), 0)), nil
+}
+
+func (queue *MultiQueue[T, K]) HeapsAmount() int {
+	return len(queue.heaps)
+}
+
+func (queue *MultiQueue[T, K]) pickTwo() (int, int) {
+	// Two different heaps in increasing order, which is also the order of locking them.
+	if len(queue.heaps) == 1 {
+		return 0, 0
+	}
+	first := rand.IntN(len(queue.heaps))
+	second := rand.IntN(len(queue.heaps) - 1)
+	if second >= first {
+		second++
+	}
+	return min(first, second), max(first, second)
+}
+
+func (queue *MultiQueue[T, K]) deleteSmaller(first, second int) (bool, item[T, K]) {
+	left, right := &queue.heaps[first], &queue.heaps[second]
+	left.mutex.Lock()
+	defer left.mutex.Unlock()
+	if right != left {
+		right.mutex.Lock()
+		defer right.mutex.Unlock()
+	}
+
+	chosen := left
+	if len(left.items) == 0 || len(right.items) > 0 && cmp.Less(right.items[0].key, left.items[0].key) {
+		chosen = right
+	}
+	if len(chosen.items) == 0 {
+		return false, item[T, K]{}
+	}
+	return true, chosen.pop()
+}
+
+func (current *heap[T, K]) push(fresh item[T, K]) {
+	current.items = append(current.items, fresh)
+	// Sift up.
+	child := len(current.items) - 1
+	for child > 0 {
+		parent := (child - 1) / 2
+		if !cmp.Less(current.items[child].key, current.items[parent].key) {
+			break
+		}
+		current.items[child], current.items[parent] = current.items[parent], current.items[child]
+		child = parent
+	}
+}
+
+func (current *heap[T, K]) pop() item[T, K] {
+	top := current.items[0]
+	last := len(current.items) - 1
+	current.items[0] = current.items[last]
+	current.items[last] = item[T, K]{}
+	current.items = current.items[:last]
+	// Sift down.
+	parent := 0
+	for {
+		smallest := parent
+		for _, child := range []int{2*parent + 1, 2*parent + 2} {
+			if child < len(current.items) && cmp.Less(current.items[child].key, current.items[smallest].key) {
+				smallest = child
+			}
+		}
+		if smallest == parent {
+			return top
+		}
+		current.items[parent], current.items[smallest] = current.items[smallest], current.items[parent]
+		parent = smallest
+	}
+}
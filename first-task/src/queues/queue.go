@@ -0,0 +1,27 @@
+package queues
+
+import (
+	"cmp"
+	"context"
+)
+
+type BoundedQueue[T any] interface {
+	TryEnqueue(T) error
+	TryDequeue() (T, error)
+	Enqueue(context.Context, T) error
+	Dequeue(context.Context) (T, error)
+	Len() (int, error)
+	Cap() int
+}
+
+type PriorityQueue[T any, K cmp.Ordered] interface {
+	Insert(K, T) error
+	DeleteMin() (K, T, error)
+	Len() (int, error)
+}
+
+const (
+	EmptyQueueError      = "Queue is already empty."
+	FullQueueError       = "Queue is already full."
+	QueueNilPointerError = "The queue pointer is nil."
+)
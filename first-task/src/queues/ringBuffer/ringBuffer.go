@@ -0,0 +1,178 @@
+package ringBuffer
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"src/queues"
+	"sync/atomic"
+	"time"
+)
+
+// Bounded multi-producer multi-consumer queue by Dmitry Vyukov.
+// Every slot carries a sequence number that tells whose turn it is:
+// sequence == position means the slot is free for the producer of this position,
+// sequence == position + 1 means the slot holds the value for the consumer of this position.
+
+type slot[T any] struct {
+	sequence atomic.Uint64
+	value    T
+}
+
+type padding [64]byte // Keeps the hot counters in different cache lines.
+
+type RingBuffer[T any] struct {
+	_          padding
+	enqueuePos atomic.Uint64
+	_          padding
+	dequeuePos atomic.Uint64
+	_          padding
+	slots      []slot[T]
+	mask       uint64
+}
+
+const (
+	spinsBeforeSleep = 64
+	maxBackoff       = time.Millisecond
+)
+
+func FreshRingBuffer[T any](capacity int) *RingBuffer[T] {
+	// New queue instance, the capacity is rounded up to the nearest power of two.
+	// A single slot can not tell "filled on this lap" from "free on the next lap", so there are at least two.
+	size := 2
+	for size < capacity {
+		size <<= 1
+	}
+	buffer := &RingBuffer[T]{slots: make([]slot[T], size), mask: uint64(size - 1)}
+	for i := range buffer.slots {
+		buffer.slots[i].sequence.Store(uint64(i))
+	}
+	return buffer
+}
+
+func (buffer *RingBuffer[T]) TryEnqueue(value T) error {
+	if buffer == nil {
+		return errors.New(queues.QueueNilPointerError)
+	}
+	position := buffer.enqueuePos.Load()
+	for {
+		current := &buffer.slots[position&buffer.mask]
+		difference := int64(current.sequence.Load() - position)
+		if difference == 0 {
+			// The slot is free: reserve the position and publish the value by moving the sequence.
+			if buffer.enqueuePos.CompareAndSwap(position, position+1) {
+				current.value = value
+				current.sequence.Store(position + 1)
+				return nil
+			}
+			position = buffer.enqueuePos.Load()
+		} else if difference < 0 {
+			// The slot still holds a value of the previous lap.
+			return errors.New(queues.FullQueueError)
+		} else {
+			// Another producer has already taken this position.
+			position = buffer.enqueuePos.Load()
+		}
+	}
+}
+
+func (buffer *RingBuffer[T]) TryDequeue() (T, error) {
+	if buffer == nil {
+		return *(new(T)), errors.New(queues.QueueNilPointerError)
+	}
+	position := buffer.dequeuePos.Load()
+	for {
+		current := &buffer.slots[position&buffer.mask]
+		difference := int64(current.sequence.Load() - (position + 1))
+		if difference == 0 {
+			// The slot is filled: reserve the position and free the slot for the next lap.
+			if buffer.dequeuePos.CompareAndSwap(position, position+1) {
+				value := current.value
+				current.value = *(new(T))
+				current.sequence.Store(position + buffer.mask + 1)
+				return value, nil
+			}
+			position = buffer.dequeuePos.Load()
+		} else if difference < 0 {
+			// The producer of this position has not come yet.
+			return *(new(T)), errors.New(queues.EmptyQueueError)
+		} else {
+			// Another consumer has already taken this position.
+			position = buffer.dequeuePos.Load()
+		}
+	}
+}
+
+func (buffer *RingBuffer[T]) Enqueue(ctx context.Context, value T) error {
+	// Blocking variant: waits for a free slot until the context is done.
+	if buffer == nil {
+		return errors.New(queues.QueueNilPointerError)
+	}
+	wait := backoff{}
+	for {
+		err := buffer.TryEnqueue(value)
+		if err == nil {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		wait.pause()
+	}
+}
+
+func (buffer *RingBuffer[T]) Dequeue(ctx context.Context) (T, error) {
+	// Blocking variant: waits for a value until the context is done.
+	if buffer == nil {
+		return *(new(T)), errors.New(queues.QueueNilPointerError)
+	}
+	wait := backoff{}
+	for {
+		value, err := buffer.TryDequeue()
+		if err == nil {
+			return value, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return *(new(T)), err
+		}
+		wait.pause()
+	}
+}
+
+func (buffer *RingBuffer[T]) Len() (int, error) {
+	// The result is exact only when there are no concurrent operations.
+	if buffer == nil {
+		return 0, errors.New(queues.QueueNilPointerError)
+	}
+	// The dequeue position is loaded first, so it can never overtake the enqueue position.
+	dequeuePos := buffer.dequeuePos.Load()
+	enqueuePos := buffer.enqueuePos.Load()
+	return int(min(enqueuePos-dequeuePos, buffer.mask+1)), nil
+}
+
+func (buffer *RingBuffer[T]) Cap() int {
+	if buffer == nil {
+		return 0
+	}
+	return len(buffer.slots)
+}
+
+type backoff struct {
+	spins int
+	sleep time.Duration
+}
+
+func (wait *backoff) pause() {
+	// Yield the processor first, then sleep for exponentially growing intervals.
+	if wait.spins < spinsBeforeSleep {
+		wait.spins++
+		runtime.Gosched()
+		return
+	}
+	if wait.sleep == 0 {
+		wait.sleep = time.Microsecond
+	} else if wait.sleep < maxBackoff {
+		wait.sleep *= 2
+	}
+	time.Sleep(wait.sleep)
+}
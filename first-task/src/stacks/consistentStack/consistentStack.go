@@ -20,6 +20,9 @@ func FreshConsistentStack[T any]() *Stack[T] {
 }
 
 func (stack *Stack[T]) Peek() (T, error) {
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
 	if stack.top == nil {
 		return *(new(T)), errors.New(stacks.EmptyStackError)
 	}
@@ -0,0 +1,72 @@
+package consistentStack
+
+import (
+	"errors"
+	"src/stacks"
+)
+
+// Encoding of the stack, the elements are listed from the top to the bottom.
+// Decoding replaces the whole content of the stack.
+
+func (stack *Stack[T]) snapshot() []T {
+	elements := []T{}
+	for current := stack.top; current != nil; current = current.next {
+		elements = append(elements, current.value)
+	}
+	return elements
+}
+
+func (stack *Stack[T]) restore(elements []T) {
+	// Elements go from the top to the bottom, so the chain is built from the end.
+	var top *cell[T]
+	for i := len(elements) - 1; i >= 0; i-- {
+		top = &cell[T]{value: elements[i], next: top}
+	}
+	stack.top = top
+}
+
+func (stack *Stack[T]) MarshalJSON() ([]byte, error) {
+	if stack == nil {
+		return nil, errors.New(stacks.StackNilPointerError)
+	}
+	return stacks.EncodeJSON(stack.snapshot())
+}
+
+func (stack *Stack[T]) UnmarshalJSON(data []byte) error {
+	if stack == nil {
+		return errors.New(stacks.StackNilPointerError)
+	}
+	elements, err := stacks.DecodeJSON[T](data)
+	if err != nil {
+		return err
+	}
+	stack.restore(elements)
+	return nil
+}
+
+func (stack *Stack[T]) MarshalBinary() ([]byte, error) {
+	if stack == nil {
+		return nil, errors.New(stacks.StackNilPointerError)
+	}
+	return stacks.EncodeBinary(stack.snapshot())
+}
+
+func (stack *Stack[T]) UnmarshalBinary(data []byte) error {
+	if stack == nil {
+		return errors.New(stacks.StackNilPointerError)
+	}
+	elements, err := stacks.DecodeBinary[T](data)
+	if err != nil {
+		return err
+	}
+	stack.restore(elements)
+	return nil
+}
+
+func (stack *Stack[T]) GobEncode() ([]byte, error) {
+	return stack.MarshalBinary()
+}
+
+func (stack *Stack[T]) GobDecode(data []byte) error {
+	return stack.UnmarshalBinary(data)
+}
@@ -0,0 +1,217 @@
+package dualStack
+
+import (
+	"context"
+	"errors"
+	"src/stacks"
+	"sync/atomic"
+	"time"
+)
+
+// Dual stack by Scherer and Scott. The stack holds either data nodes or reservations of waiting Pop calls.
+// A Push that finds a reservation on the top puts a fulfilling node over it, matches the two
+// and removes both, so the value is handed off directly to the waiting goroutine.
+// Every step is a single CAS, and any goroutine that sees a fulfilling node on the top
+// finishes the hand-off instead of waiting for its owner.
+
+type mode int
+
+const (
+	data        mode = 0
+	reservation mode = 1
+	fulfilling  mode = 2
+)
+
+type node[T any] struct {
+	value T
+	mode  mode
+	next  atomic.Pointer[node[T]]
+	match atomic.Pointer[node[T]] // The fulfilling node of a reservation, or the reservation itself if it was cancelled.
+	wake  chan struct{}           // Closed when the reservation is matched.
+}
+
+type Stack[T any] struct {
+	top atomic.Pointer[node[T]]
+}
+
+func FreshDualStack[T any]() *Stack[T] {
+	// New stack instance.
+	return &Stack[T]{}
+}
+
+func (stack *Stack[T]) Peek() (T, error) {
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	top := stack.top.Load()
+	if top == nil || top.mode != data {
+		return *(new(T)), errors.New(stacks.EmptyStackError)
+	}
+	return top.value, nil
+}
+
+func (stack *Stack[T]) Push(value T) error {
+	if stack == nil {
+		return errors.New(stacks.StackNilPointerError)
+	}
+	for {
+		top := stack.top.Load()
+		switch {
+		case top == nil || top.mode == data:
+			// Nobody is waiting, so this is an ordinary push.
+			newTop := &node[T]{value: value, mode: data}
+			newTop.next.Store(top)
+			if stack.top.CompareAndSwap(top, newTop) {
+				return nil
+			}
+		case top.mode == reservation:
+			if top.isCancelled() {
+				stack.top.CompareAndSwap(top, top.next.Load())
+				continue
+			}
+			// Cover the reservations with a fulfilling node and hand the value to the first live one.
+			fulfiller := &node[T]{value: value, mode: fulfilling}
+			fulfiller.next.Store(top)
+			if !stack.top.CompareAndSwap(top, fulfiller) {
+				continue
+			}
+			for {
+				if stack.fulfil(fulfiller) {
+					return nil
+				}
+				if fulfiller.next.Load() == nil {
+					// All reservations below were cancelled, start over with an ordinary push.
+					break
+				}
+			}
+		default:
+			// Someone else is in the middle of a hand-off, help to finish it.
+			stack.fulfil(top)
+		}
+	}
+}
+
+func (stack *Stack[T]) Pop() (T, error) {
+	// Waits for a complementary Push as long as it takes.
+	return stack.PopContext(context.Background())
+}
+
+func (stack *Stack[T]) PopTimeout(timeout time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	value, err := stack.PopContext(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return value, errors.New(stacks.PopTimeoutError)
+	}
+	return value, err
+}
+
+func (stack *Stack[T]) PopContext(ctx context.Context) (T, error) {
+	// Takes the top value, or leaves a reservation and waits until a Push fulfils it
+	// or the context is done. A cancelled reservation is never fulfilled afterwards.
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	for {
+		top := stack.top.Load()
+		switch {
+		case top != nil && top.mode == data:
+			if stack.top.CompareAndSwap(top, top.next.Load()) {
+				return top.value, nil
+			}
+		case top != nil && top.mode == fulfilling:
+			stack.fulfil(top)
+		case top != nil && top.isCancelled():
+			stack.top.CompareAndSwap(top, top.next.Load())
+		default:
+			// The stack is empty or holds only reservations: wait in line.
+			request := &node[T]{mode: reservation, wake: make(chan struct{})}
+			request.next.Store(top)
+			if stack.top.CompareAndSwap(top, request) {
+				return stack.await(ctx, request)
+			}
+		}
+	}
+}
+
+func (stack *Stack[T]) TryPop() (T, error) {
+	// Never leaves a reservation, fails with EmptyStackError just like the other stacks.
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	for {
+		top := stack.top.Load()
+		switch {
+		case top == nil || top.mode == reservation:
+			return *(new(T)), errors.New(stacks.EmptyStackError)
+		case top.mode == fulfilling:
+			stack.fulfil(top)
+		default:
+			if stack.top.CompareAndSwap(top, top.next.Load()) {
+				return top.value, nil
+			}
+		}
+	}
+}
+
+func (stack *Stack[T]) Len() (int, error) {
+	// Only data nodes are counted, waiting reservations are not elements of the stack.
+	if stack == nil {
+		return 0, errors.New(stacks.StackNilPointerError)
+	}
+	size := 0
+	current := stack.top.Load()
+	for current != nil {
+		if current.mode == data {
+			size++
+		}
+		current = current.next.Load()
+	}
+	return size, nil
+}
+
+func (stack *Stack[T]) await(ctx context.Context, request *node[T]) (T, error) {
+	select {
+	case <-request.wake:
+	case <-ctx.Done():
+		if request.match.CompareAndSwap(nil, request) {
+			// The reservation is cancelled, remove it right away if it is still on the top.
+			stack.top.CompareAndSwap(request, request.next.Load())
+			return *(new(T)), ctx.Err()
+		}
+		// A Push has matched the reservation at the same moment, so the value is ours.
+		<-request.wake
+	}
+	fulfiller := request.match.Load()
+	stack.top.CompareAndSwap(fulfiller, request.next.Load())
+	return fulfiller.value, nil
+}
+
+func (stack *Stack[T]) fulfil(fulfiller *node[T]) bool {
+	// One step of a hand-off: match the reservation right under the fulfilling node
+	// and pop both of them, or unlink the reservation if it was cancelled.
+	request := fulfiller.next.Load()
+	if request == nil {
+		stack.top.CompareAndSwap(fulfiller, nil)
+		return false
+	}
+	next := request.next.Load()
+	if request.tryMatch(fulfiller) {
+		stack.top.CompareAndSwap(fulfiller, next)
+		return true
+	}
+	fulfiller.next.CompareAndSwap(request, next)
+	return false
+}
+
+func (request *node[T]) tryMatch(fulfiller *node[T]) bool {
+	if request.match.CompareAndSwap(nil, fulfiller) {
+		close(request.wake)
+		return true
+	}
+	return request.match.Load() == fulfiller
+}
+
+func (request *node[T]) isCancelled() bool {
+	return request.match.Load() == request
+}
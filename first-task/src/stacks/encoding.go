@@ -0,0 +1,42 @@
+package stacks
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Shared encoding of stack snapshots. A snapshot lists the elements from the top to the bottom,
+// JSON stores it as an array and the binary form is the gob encoding of the slice.
+// Gob can encode any type made of exported fields, so it suits an arbitrary T.
+
+func EncodeJSON[T any](elements []T) ([]byte, error) {
+	if elements == nil {
+		elements = []T{} // An empty stack is "[]" rather than "null".
+	}
+	return json.Marshal(elements)
+}
+
+func DecodeJSON[T any](data []byte) ([]T, error) {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
+func EncodeBinary[T any](elements []T) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	if err := gob.NewEncoder(&buffer).Encode(elements); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func DecodeBinary[T any](data []byte) ([]T, error) {
+	var elements []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&elements); err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
@@ -0,0 +1,51 @@
+package stacks
+
+// Optional hooks that let a stack report what happens inside its operations.
+// A stack without an observer only pays for a nil check.
+
+type Operation int
+
+const (
+	PushOperation Operation = 0
+	PopOperation  Operation = 1
+)
+
+type Event int
+
+const (
+	CasRetry           Event = 0 // The CAS on the top failed.
+	EliminationAttempt Event = 1 // The operation went to the elimination array.
+	ExchangeSuccess    Event = 2 // The operation met a complementary one in the elimination array.
+	ExchangeTimeout    Event = 3 // Nobody came to the exchanger in time.
+)
+
+type Observer interface {
+	Begin(Operation) func() // Called when an operation starts, the returned function is called when it ends.
+	Event(Operation, Event)
+}
+
+func (operation Operation) String() string {
+	switch operation {
+	case PushOperation:
+		return "push"
+	case PopOperation:
+		return "pop"
+	default:
+		return "unknown"
+	}
+}
+
+func (event Event) String() string {
+	switch event {
+	case CasRetry:
+		return "cas retry"
+	case EliminationAttempt:
+		return "elimination attempt"
+	case ExchangeSuccess:
+		return "exchange success"
+	case ExchangeTimeout:
+		return "exchange timeout"
+	default:
+		return "unknown"
+	}
+}
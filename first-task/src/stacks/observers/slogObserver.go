@@ -0,0 +1,37 @@
+package observers
+
+import (
+	"context"
+	"log/slog"
+	"src/stacks"
+	"time"
+)
+
+// Observer that writes every operation and event to a logger at the debug level.
+// It is meant for debugging, so it is much slower than the stack itself.
+
+type SlogObserver struct {
+	logger *slog.Logger
+}
+
+func FreshSlogObserver(logger *slog.Logger) *SlogObserver {
+	// New observer instance, the default logger is used if logger is nil.
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogObserver{logger: logger}
+}
+
+func (observer *SlogObserver) Begin(operation stacks.Operation) func() {
+	if !observer.logger.Enabled(context.Background(), slog.LevelDebug) {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		observer.logger.Debug("stack operation", "operation", operation.String(), "duration", time.Since(start))
+	}
+}
+
+func (observer *SlogObserver) Event(operation stacks.Operation, event stacks.Event) {
+	observer.logger.Debug("stack event", "operation", operation.String(), "event", event.String())
+}
@@ -0,0 +1,37 @@
+package observers
+
+import (
+	"context"
+	"runtime/trace"
+	"src/stacks"
+)
+
+// Observer for "go tool trace". The whole life of the observer is a single trace task,
+// every operation is a region inside it and every event is a log message of the task.
+
+const traceCategory = "stack"
+
+type TraceObserver struct {
+	ctx  context.Context
+	task *trace.Task
+}
+
+func FreshTraceObserver(ctx context.Context, name string) *TraceObserver {
+	// New observer instance, the task is shown under the given name.
+	taskCtx, task := trace.NewTask(ctx, name)
+	return &TraceObserver{ctx: taskCtx, task: task}
+}
+
+func (observer *TraceObserver) Begin(operation stacks.Operation) func() {
+	// Regions must end in the goroutine they were started in, which holds for a stack operation.
+	return trace.StartRegion(observer.ctx, operation.String()).End
+}
+
+func (observer *TraceObserver) Event(operation stacks.Operation, event stacks.Event) {
+	trace.Log(observer.ctx, traceCategory, operation.String()+": "+event.String())
+}
+
+func (observer *TraceObserver) End() {
+	// Ends the task, the observer must not be used afterwards.
+	observer.task.End()
+}
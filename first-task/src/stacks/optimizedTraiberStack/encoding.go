@@ -0,0 +1,75 @@
+package optimizedTraiberStack
+
+import (
+	"errors"
+	"src/stacks"
+)
+
+// Encoding of the stack. Exchangers only pass elements between a push and a pop in flight,
+// so the cells reachable from the top are the whole content and the exchangers are not encoded.
+// Decoding replaces the cells with one store of the top and keeps the exchangers of the constructor.
+
+func (stack *Stack[T]) snapshot() []T {
+	elements := []T{}
+	for current := stack.top.Load(); current != nil; current = current.next.Load() {
+		elements = append(elements, current.value)
+	}
+	return elements
+}
+
+func (stack *Stack[T]) restore(elements []T) {
+	// Elements go from the top to the bottom, so the chain is built from the end.
+	var top *cell[T]
+	for i := len(elements) - 1; i >= 0; i-- {
+		fresh := &cell[T]{value: elements[i]}
+		fresh.next.Store(top)
+		top = fresh
+	}
+	stack.top.Store(top)
+}
+
+func (stack *Stack[T]) MarshalJSON() ([]byte, error) {
+	if stack == nil {
+		return nil, errors.New(stacks.StackNilPointerError)
+	}
+	return stacks.EncodeJSON(stack.snapshot())
+}
+
+func (stack *Stack[T]) UnmarshalJSON(data []byte) error {
+	if stack == nil {
+		return errors.New(stacks.StackNilPointerError)
+	}
+	elements, err := stacks.DecodeJSON[T](data)
+	if err != nil {
+		return err
+	}
+	stack.restore(elements)
+	return nil
+}
+
+func (stack *Stack[T]) MarshalBinary() ([]byte, error) {
+	if stack == nil {
+		return nil, errors.New(stacks.StackNilPointerError)
+	}
+	return stacks.EncodeBinary(stack.snapshot())
+}
+
+func (stack *Stack[T]) UnmarshalBinary(data []byte) error {
+	if stack == nil {
+		return errors.New(stacks.StackNilPointerError)
+	}
+	elements, err := stacks.DecodeBinary[T](data)
+	if err != nil {
+		return err
+	}
+	stack.restore(elements)
+	return nil
+}
+
+func (stack *Stack[T]) GobEncode() ([]byte, error) {
+	return stack.MarshalBinary()
+}
+
+func (stack *Stack[T]) GobDecode(data []byte) error {
+	return stack.UnmarshalBinary(data)
+}
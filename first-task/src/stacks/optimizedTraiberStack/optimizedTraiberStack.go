@@ -14,21 +14,30 @@ type cell[T any] struct {
 type Stack[T any] struct {
 	top            atomic.Pointer[cell[T]]
 	exchangerArray exchangersArray[T]
+	observer       stacks.Observer // Optional, nil means that nobody watches the stack.
 }
 
+const exchangersPower = 10  // The number of exchangers in the elimination array.
+const exchangeReplays = 500 // The number of attempts to make an exchange.
+
 func FreshOptimizedTraiberStack[T any]() *Stack[T] {
 	// New stack instance.
-	return &Stack[T]{exchangerArray: freshExchangersArray[T](10, 500)}
+	return &Stack[T]{exchangerArray: freshExchangersArray[T](exchangersPower, exchangeReplays)}
+}
+
+func (stack *Stack[T]) SetObserver(observer stacks.Observer) {
+	// Must be called before the stack is shared between goroutines.
+	stack.observer = observer
 }
 
 func (stack *Stack[T]) Peek() (T, error) {
 
 	if stack == nil {
-		return *(new(T)), errors.New("The consistentStack pointer is nil.")
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
 	}
 
 	if stack.top.Load() == nil {
-		return *(new(T)), errors.New("Stack is already empty.")
+		return *(new(T)), errors.New(stacks.EmptyStackError)
 	}
 
 	return stack.top.Load().value, nil
@@ -43,7 +52,10 @@ func (stack *Stack[T]) primitePush(value T) bool {
 
 func (stack *Stack[T]) Push(value T) error {
 	if stack == nil {
-		return errors.New("The consistentStack pointer is nil.")
+		return errors.New(stacks.StackNilPointerError)
+	}
+	if stack.observer != nil {
+		defer stack.observer.Begin(stacks.PushOperation)()
 	}
 	for {
 		if stack.primitePush(value) { // Try to push the element.
@@ -51,11 +63,15 @@ func (stack *Stack[T]) Push(value T) error {
 		}
 		// If it was not possible to push an element,
 		// put it in the array of exchangers and try to carry out the exchange.
+		stack.notify(stacks.PushOperation, stacks.CasRetry)
+		stack.notify(stacks.PushOperation, stacks.EliminationAttempt)
 		_, err := stack.exchangerArray.visit(&value)
 		if err == nil {
+			stack.notify(stacks.PushOperation, stacks.ExchangeSuccess)
 			return nil
 		}
 		// If the exchange also fails - start over.
+		stack.notify(stacks.PushOperation, stacks.ExchangeTimeout)
 	}
 	return nil
 }
@@ -76,6 +92,12 @@ func (stack *Stack[T]) primitivePop() (T, error) {
 }
 
 func (stack *Stack[T]) Pop() (T, error) {
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	if stack.observer != nil {
+		defer stack.observer.Begin(stacks.PopOperation)()
+	}
 	for {
 		value, err := stack.primitivePop() // Try to remove the element.
 		if err == nil {
@@ -85,10 +107,14 @@ func (stack *Stack[T]) Pop() (T, error) {
 		if err.Error() == stacks.UnsuccessfulPrimitivePop {
 			// If it was not possible to delete an element,
 			// put it in the array of exchangers and try to carry out the exchange.
+			stack.notify(stacks.PopOperation, stacks.CasRetry)
+			stack.notify(stacks.PopOperation, stacks.EliminationAttempt)
 			element, err := stack.exchangerArray.visit(nil)
 			if err == nil {
+				stack.notify(stacks.PopOperation, stacks.ExchangeSuccess)
 				return *element, nil
 			}
+			stack.notify(stacks.PopOperation, stacks.ExchangeTimeout)
 		} else {
 			return *new(T), err
 		}
@@ -96,6 +122,12 @@ func (stack *Stack[T]) Pop() (T, error) {
 	}
 }
 
+func (stack *Stack[T]) notify(operation stacks.Operation, event stacks.Event) {
+	if stack.observer != nil {
+		stack.observer.Event(operation, event)
+	}
+}
+
 func (stack *Stack[T]) Len() (int, error) {
 	if stack == nil {
 		return 0, errors.New(stacks.StackNilPointerError)
@@ -0,0 +1,55 @@
+package persistentStack
+
+import (
+	"errors"
+	"src/stacks"
+)
+
+// Purely functional stack. Push and Pop never change a stack, they return a new version
+// that shares all the cells below the top with the old one, so every version stays valid
+// and keeping a snapshot costs nothing.
+
+type cell[T any] struct {
+	value T
+	next  *cell[T]
+	size  int // The amount of cells from this one to the bottom.
+}
+
+type Stack[T any] struct {
+	top *cell[T] // Nil for the empty stack, so the zero value is ready to use.
+}
+
+func FreshPersistentStack[T any]() Stack[T] {
+	// New empty stack.
+	return Stack[T]{}
+}
+
+func (stack Stack[T]) Push(value T) Stack[T] {
+	return Stack[T]{top: &cell[T]{value: value, next: stack.top, size: stack.Len() + 1}}
+}
+
+func (stack Stack[T]) Pop() (Stack[T], T, error) {
+	if stack.top == nil {
+		return stack, *(new(T)), errors.New(stacks.EmptyStackError)
+	}
+	return Stack[T]{top: stack.top.next}, stack.top.value, nil
+}
+
+func (stack Stack[T]) Peek() (T, error) {
+	if stack.top == nil {
+		return *(new(T)), errors.New(stacks.EmptyStackError)
+	}
+	return stack.top.value, nil
+}
+
+func (stack Stack[T]) Len() int {
+	if stack.top == nil {
+		return 0
+	}
+	return stack.top.size
+}
+
+func (stack Stack[T]) Same(other Stack[T]) bool {
+	// Reports whether both are the same version, not just equal elements.
+	return stack.top == other.top
+}
@@ -0,0 +1,132 @@
+package persistentStack
+
+import (
+	"errors"
+	"src/stacks"
+	"sync"
+	"sync/atomic"
+)
+
+// Concurrent stack built on the persistent one. The current version is published through
+// an atomic pointer, every operation prepares the next version and installs it with a CAS.
+// Since versions never change, any of them can be tagged and published again later.
+
+type Versioned[T any] struct {
+	current atomic.Pointer[cell[T]]
+	tags    map[string]Stack[T]
+	mutex   sync.Mutex // Protects tags.
+}
+
+func FreshVersionedStack[T any]() *Versioned[T] {
+	// New stack instance.
+	return &Versioned[T]{tags: make(map[string]Stack[T])}
+}
+
+func (stack *Versioned[T]) Push(value T) error {
+	if stack == nil {
+		return errors.New(stacks.StackNilPointerError)
+	}
+	for {
+		old := stack.latest()
+		if stack.current.CompareAndSwap(old.top, old.Push(value).top) {
+			return nil
+		}
+	}
+}
+
+func (stack *Versioned[T]) Pop() (T, error) {
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	for {
+		old := stack.latest()
+		next, value, err := old.Pop()
+		if err != nil {
+			return value, err
+		}
+		if stack.current.CompareAndSwap(old.top, next.top) {
+			return value, nil
+		}
+	}
+}
+
+func (stack *Versioned[T]) Peek() (T, error) {
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	return stack.latest().Peek()
+}
+
+func (stack *Versioned[T]) Len() (int, error) {
+	if stack == nil {
+		return 0, errors.New(stacks.StackNilPointerError)
+	}
+	return stack.latest().Len(), nil
+}
+
+func (stack *Versioned[T]) Current() (Stack[T], error) {
+	// Snapshot of the stack in O(1).
+	if stack == nil {
+		return Stack[T]{}, errors.New(stacks.StackNilPointerError)
+	}
+	return stack.latest(), nil
+}
+
+func (stack *Versioned[T]) Publish(version Stack[T]) (Stack[T], error) {
+	// Makes the given version current and returns the replaced one.
+	if stack == nil {
+		return Stack[T]{}, errors.New(stacks.StackNilPointerError)
+	}
+	return Stack[T]{top: stack.current.Swap(version.top)}, nil
+}
+
+func (stack *Versioned[T]) Tag(name string) (Stack[T], error) {
+	// Remembers the current version under the name, an older version with this name is forgotten.
+	if stack == nil {
+		return Stack[T]{}, errors.New(stacks.StackNilPointerError)
+	}
+	version := stack.latest()
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	if stack.tags == nil {
+		stack.tags = make(map[string]Stack[T])
+	}
+	stack.tags[name] = version
+	return version, nil
+}
+
+func (stack *Versioned[T]) Version(name string) (Stack[T], error) {
+	if stack == nil {
+		return Stack[T]{}, errors.New(stacks.StackNilPointerError)
+	}
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	version, ok := stack.tags[name]
+	if !ok {
+		return Stack[T]{}, errors.New(stacks.UnknownVersionError)
+	}
+	return version, nil
+}
+
+func (stack *Versioned[T]) Restore(name string) error {
+	// Makes the tagged version current again, the operations made after it are discarded.
+	version, err := stack.Version(name)
+	if err != nil {
+		return err
+	}
+	_, err = stack.Publish(version)
+	return err
+}
+
+func (stack *Versioned[T]) Untag(name string) {
+	if stack == nil {
+		return
+	}
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	delete(stack.tags, name)
+}
+
+func (stack *Versioned[T]) latest() Stack[T] {
+	return Stack[T]{top: stack.current.Load()}
+}
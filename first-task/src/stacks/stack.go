@@ -11,4 +11,7 @@ const (
 	EmptyStackError          = "Stack is already empty."
 	StackNilPointerError     = "The consistentStack pointer is nil."
 	UnsuccessfulPrimitivePop = "Failed to remove element: trying to find a complementary operation."
+	PopTimeoutError          = "Timed out waiting for a complementary push."
+	UnknownVersionError      = "There is no stack version with this tag."
+	MarkInvalidatedError     = "The marked element has been popped or the mark belongs to another stack."
 )
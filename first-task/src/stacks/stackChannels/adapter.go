@@ -0,0 +1,101 @@
+package stackChannels
+
+import (
+	"context"
+	"src/stacks"
+)
+
+// Adapter that lets channel-based code talk to a stack. Everything sent to In is pushed,
+// Out yields the elements in LIFO order: a receiver always gets the most recently pushed one.
+// A single goroutine serves both channels and owns the stack while it runs.
+//
+// Shutdown: closing In lets the adapter hand out the elements left and then close Out.
+// Cancelling the context stops the adapter at once, the undelivered elements stay in the stack
+// and Out is closed. In both cases the goroutine exits and Done is closed.
+
+type Adapter[T any] struct {
+	in       chan T
+	out      chan T
+	done     chan struct{}
+	stack    stacks.Stack[T]
+	capacity int   // The maximum amount of held elements, zero for no limit.
+	err      error // Why the adapter stopped, readable after Done.
+}
+
+func FreshAdapter[T any](ctx context.Context, stack stacks.Stack[T], capacity int) *Adapter[T] {
+	// New adapter instance. When capacity elements are held, sends to In block until
+	// somebody receives from Out, which gives back-pressure to the producers.
+	adapter := &Adapter[T]{
+		in:       make(chan T),
+		out:      make(chan T),
+		done:     make(chan struct{}),
+		stack:    stack,
+		capacity: max(capacity, 0),
+	}
+	go adapter.serve(ctx)
+	return adapter
+}
+
+func (adapter *Adapter[T]) In() chan<- T {
+	// The caller closes it when there is nothing more to push.
+	return adapter.in
+}
+
+func (adapter *Adapter[T]) Out() <-chan T {
+	return adapter.out
+}
+
+func (adapter *Adapter[T]) Done() <-chan struct{} {
+	return adapter.done
+}
+
+func (adapter *Adapter[T]) Err() error {
+	// Nil after a normal shutdown, the context error after cancellation.
+	<-adapter.done
+	return adapter.err
+}
+
+func (adapter *Adapter[T]) serve(ctx context.Context) {
+	defer close(adapter.done)
+	defer close(adapter.out)
+
+	// The top of the stack is kept aside, ready to be sent.
+	held, _ := adapter.stack.Len()
+	pending, err := adapter.stack.Pop()
+	hasPending := err == nil
+
+	in := adapter.in
+	for in != nil || hasPending {
+		// A nil channel disables its case: nothing to send, or no room to receive.
+		receive, send := in, adapter.out
+		if adapter.capacity > 0 && held >= adapter.capacity {
+			receive = nil
+		}
+		if !hasPending {
+			send = nil
+		}
+
+		select {
+		case value, ok := <-receive:
+			if !ok {
+				in = nil
+				continue
+			}
+			if hasPending {
+				adapter.stack.Push(pending)
+			}
+			pending, hasPending = value, true
+			held++
+		case send <- pending:
+			held--
+			pending, err = adapter.stack.Pop()
+			hasPending = err == nil
+		case <-ctx.Done():
+			if hasPending {
+				adapter.stack.Push(pending)
+			}
+			adapter.err = ctx.Err()
+			return
+		}
+	}
+}
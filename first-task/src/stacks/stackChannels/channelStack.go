@@ -0,0 +1,97 @@
+package stackChannels
+
+import (
+	"context"
+	"errors"
+	"src/stacks"
+	"src/stacks/traiberStack"
+	"sync/atomic"
+)
+
+// Stack fed by a channel. Before every operation the elements already waiting in the channel
+// are moved to the stack, as if they had been pushed in the order of arrival.
+// No goroutine is started: the channel is only read inside the calls.
+// A nil source is treated as a closed channel, so the stack is a plain concurrent stack.
+
+type ChannelStack[T any] struct {
+	source <-chan T
+	closed atomic.Bool // The source was closed, there is no need to look at it anymore.
+	stack  *traiberStack.Stack[T]
+}
+
+func FreshChannelStack[T any](source <-chan T) *ChannelStack[T] {
+	// New stack instance.
+	stack := &ChannelStack[T]{source: source, stack: traiberStack.FreshTraiberStack[T]()}
+	stack.closed.Store(source == nil)
+	return stack
+}
+
+func (stack *ChannelStack[T]) drain() {
+	for !stack.closed.Load() {
+		select {
+		case value, ok := <-stack.source:
+			if !ok {
+				stack.closed.Store(true)
+				return
+			}
+			stack.stack.Push(value)
+		default:
+			return
+		}
+	}
+}
+
+func (stack *ChannelStack[T]) Push(value T) error {
+	if stack == nil {
+		return errors.New(stacks.StackNilPointerError)
+	}
+	stack.drain()
+	return stack.stack.Push(value)
+}
+
+func (stack *ChannelStack[T]) Pop() (T, error) {
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	stack.drain()
+	return stack.stack.Pop()
+}
+
+func (stack *ChannelStack[T]) PopContext(ctx context.Context) (T, error) {
+	// Like Pop, but waits for the channel when the stack is empty.
+	// Fails with emptyStackError only when the channel is closed.
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	for {
+		value, err := stack.Pop()
+		if err == nil || stack.closed.Load() {
+			return value, err
+		}
+		select {
+		case value, ok := <-stack.source:
+			if ok {
+				return value, nil
+			}
+			stack.closed.Store(true)
+		case <-ctx.Done():
+			return *(new(T)), ctx.Err()
+		}
+	}
+}
+
+func (stack *ChannelStack[T]) Peek() (T, error) {
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	stack.drain()
+	return stack.stack.Peek()
+}
+
+func (stack *ChannelStack[T]) Len() (int, error) {
+	if stack == nil {
+		return 0, errors.New(stacks.StackNilPointerError)
+	}
+	stack.drain()
+	return stack.stack.Len()
+}
@@ -0,0 +1,553 @@
+package stacktest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"src/stacks"
+	"sync"
+	"testing"
+)
+
+// Conformance tests and benchmarks for any implementation of stacks.Stack.
+// A third-party stack only has to provide a Factory:
+//
+//	func TestMyStack(t *testing.T) {
+//		stacktest.Run(t, stacktest.Ints(func() stacks.Stack[int] { return FreshMyStack[int]() }))
+//	}
+
+const DefaultElementsAmount = 1_000_000
+
+const gorutinesAmount1 = 8
+const gorutinesAmount2 = 100
+
+type Factory[T comparable] struct {
+	New            func() stacks.Stack[T] // Returns a new empty stack.
+	Element        func(int) T            // Must return different elements for different arguments.
+	ElementsAmount int                    // Size of the large scenarios, DefaultElementsAmount if zero.
+}
+
+func Ints(newStack func() stacks.Stack[int]) Factory[int] {
+	// Factory for stacks of integers, where the i-th element is i.
+	return Factory[int]{New: newStack, Element: func(i int) int { return i }}
+}
+
+func (factory Factory[T]) elementsAmount() int {
+	if factory.ElementsAmount > 0 {
+		return factory.ElementsAmount
+	}
+	return DefaultElementsAmount
+}
+
+func Run[T comparable](t *testing.T, factory Factory[T]) {
+	// All the cases, the stack must be safe for concurrent use.
+	t.Run("Sequential", func(t *testing.T) {
+		RunSequential(t, factory)
+	})
+	t.Run("Parallel", func(t *testing.T) {
+		RunParallel(t, factory)
+	})
+}
+
+func RunSequential[T comparable](t *testing.T, factory Factory[T]) {
+	// The cases that use the stack from a single goroutine.
+	elementsAmount := factory.elementsAmount()
+
+	t.Run("Test Empty Stack Pop: ", func(t *testing.T) {
+		stack := factory.New()
+		_, err := stack.Pop()
+		checkEmptyStackError(t, err)
+	})
+
+	t.Run("Test Empty Stack Peek: ", func(t *testing.T) {
+		stack := factory.New()
+		_, err := stack.Peek()
+		checkEmptyStackError(t, err)
+	})
+
+	t.Run("Test Empty Stack Len: ", func(t *testing.T) {
+		stack := factory.New()
+		stackLen, err := stack.Len()
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+		if stackLen != 0 {
+			t.Errorf("Received stack len %d != expected stack len 0", stackLen)
+		}
+	})
+
+	t.Run("Test Not Empty Stack Peek: ", func(t *testing.T) {
+		stack := factory.New()
+		stack.Push(factory.Element(1))
+		stack.Push(factory.Element(2))
+		elem, err := stack.Peek()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if elem != factory.Element(2) {
+			t.Errorf("Received top %v != expected top %v", elem, factory.Element(2))
+		}
+		// Peek must not remove the element.
+		if stackLen, _ := stack.Len(); stackLen != 2 {
+			t.Errorf("Received stack len %d != expected stack len 2", stackLen)
+		}
+	})
+
+	t.Run("Test Stack Push and Pop: ", func(t *testing.T) {
+		stack := factory.New()
+		for i := 0; i < 10; i++ {
+			stack.Push(factory.Element(i))
+		}
+		for i := 9; i >= 0; i-- {
+			elem, err := stack.Pop()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			if elem != factory.Element(i) {
+				t.Fatalf("Received removed element %v != expected removed element %v", elem, factory.Element(i))
+			}
+		}
+		_, err := stack.Pop()
+		checkEmptyStackError(t, err)
+	})
+
+	t.Run("Test Stack Push and Pop2: ", func(t *testing.T) {
+		stack := factory.New()
+		stack.Push(factory.Element(1))
+		stack.Push(factory.Element(2))
+		stack.Push(factory.Element(3))
+		stack.Pop()
+
+		elem, err := stack.Pop()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if elem != factory.Element(2) {
+			t.Errorf("Received removed element %v != expected removed element %v", elem, factory.Element(2))
+		}
+	})
+
+	t.Run("Test Stack Duplicates: ", func(t *testing.T) {
+		// Equal elements are separate entries of the stack.
+		stack := factory.New()
+		for i := 0; i < 3; i++ {
+			stack.Push(factory.Element(7))
+		}
+		for i := 0; i < 3; i++ {
+			elem, err := stack.Pop()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			if elem != factory.Element(7) {
+				t.Fatalf("Received removed element %v != expected removed element %v", elem, factory.Element(7))
+			}
+		}
+		_, err := stack.Pop()
+		checkEmptyStackError(t, err)
+	})
+
+	t.Run("Test Stack Reuse After Empty: ", func(t *testing.T) {
+		stack := factory.New()
+		stack.Push(factory.Element(1))
+		stack.Pop()
+		stack.Pop()
+		stack.Push(factory.Element(2))
+		elem, err := stack.Peek()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if elem != factory.Element(2) {
+			t.Errorf("Received top %v != expected top %v", elem, factory.Element(2))
+		}
+	})
+
+	t.Run("Test Stack Len: ", func(t *testing.T) {
+		stack := factory.New()
+		for i := 1; i <= elementsAmount; i++ {
+			stack.Push(factory.Element(i))
+		}
+
+		stackLen, err := stack.Len()
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+		if stackLen != elementsAmount {
+			t.Errorf("Received stack len %d != expected stack len %d", stackLen, elementsAmount)
+		}
+	})
+
+	t.Run("Test Nil Stack: ", func(t *testing.T) {
+		// A nil pointer of the stack type must report an error instead of panicking.
+		stackType := reflect.TypeOf(factory.New())
+		if stackType == nil || stackType.Kind() != reflect.Pointer {
+			t.Skip("The stack is not a pointer.")
+		}
+		stack := reflect.Zero(stackType).Interface().(stacks.Stack[T])
+
+		checkNilPointerError(t, "Push", stack.Push(factory.Element(1)))
+		_, err := stack.Pop()
+		checkNilPointerError(t, "Pop", err)
+		_, err = stack.Peek()
+		checkNilPointerError(t, "Peek", err)
+		_, err = stack.Len()
+		checkNilPointerError(t, "Len", err)
+	})
+}
+
+func RunParallel[T comparable](t *testing.T, factory Factory[T]) {
+	// The cases that use the stack from many goroutines at once.
+	elementsAmount := factory.elementsAmount()
+
+	t.Run("Test push", func(t *testing.T) {
+		// Check that push works correctly and there is no data race.
+		stack := factory.New()
+		wg := sync.WaitGroup{}
+		wg.Add(elementsAmount)
+		for i := 0; i < elementsAmount; i++ {
+			go func() {
+				defer wg.Done()
+				err := stack.Push(factory.Element(i))
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+			}()
+		}
+		wg.Wait()
+
+		stackLen, err := stack.Len()
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+		if stackLen != elementsAmount {
+			t.Errorf("Received stack size %d != expected stack size %d", stackLen, elementsAmount)
+		}
+	})
+
+	t.Run("Test pop on empty stack", func(t *testing.T) {
+		// Check that pop works correctly and there is no data race.
+		stack := factory.New()
+		wg := sync.WaitGroup{}
+		wg.Add(elementsAmount)
+		for i := 0; i < elementsAmount; i++ {
+			go func() {
+				defer wg.Done()
+				_, err := stack.Pop()
+				if err == nil || err.Error() != stacks.EmptyStackError {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		stackLen, err := stack.Len()
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+		if stackLen != 0 {
+			t.Errorf("Received stack size %d != expected stack size 0", stackLen)
+		}
+	})
+
+	t.Run("Test push and pop", func(t *testing.T) {
+		// First, we launch a goroutine for every insertion (each with 1 element),
+		// then as many for deletion.
+		stack := factory.New()
+		wg := sync.WaitGroup{}
+		wg.Add(elementsAmount)
+		for i := 0; i < elementsAmount; i++ {
+			go func() {
+				defer wg.Done()
+				err := stack.Push(factory.Element(i))
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+			}()
+		}
+		wg.Wait()
+
+		wg.Add(elementsAmount)
+		for i := 0; i < elementsAmount; i++ {
+			go func() {
+				defer wg.Done()
+				_, err := stack.Pop()
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+			}()
+		}
+		wg.Wait()
+		stackLen, err := stack.Len()
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+		if stackLen != 0 {
+			t.Errorf("Received stack size %d != expected stack size 0", stackLen)
+		}
+	})
+
+	t.Run("Test push and pop duplicates", func(t *testing.T) {
+		// All the goroutines push the same element, every copy must be popped exactly once.
+		stack := factory.New()
+		duplicate := factory.Element(0)
+		perGorutine := elementsAmount / gorutinesAmount1
+		popped := make([]int, gorutinesAmount1)
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount1)
+		for i := 0; i < gorutinesAmount1; i++ {
+			go func() {
+				defer wg.Done()
+				for j := 0; j < perGorutine; j++ {
+					stack.Push(duplicate)
+					if elem, err := stack.Pop(); err == nil {
+						if elem != duplicate {
+							t.Errorf("Received removed element %v != expected removed element %v", elem, duplicate)
+						}
+						popped[i]++
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		total := 0
+		for _, amount := range popped {
+			total += amount
+		}
+		stackLen, _ := stack.Len()
+		if expected := gorutinesAmount1 * perGorutine; total+stackLen != expected {
+			t.Errorf("Received popped and left elements %d != expected pushed elements %d", total+stackLen, expected)
+		}
+	})
+}
+
+func Benchmark[T comparable](b *testing.B, factory Factory[T]) {
+	// All the benchmarks, the stack must be safe for concurrent use.
+	b.Run("Sequential", func(b *testing.B) {
+		BenchmarkSequential(b, factory)
+	})
+	b.Run("Parallel", func(b *testing.B) {
+		BenchmarkParallel(b, factory)
+	})
+}
+
+func BenchmarkSequential[T comparable](b *testing.B, factory Factory[T]) {
+	// Metrics are measured for sequential operations.
+	elementsAmount := factory.elementsAmount()
+
+	b.Run("Push", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			for j := 0; j < elementsAmount; j++ {
+				stack.Push(factory.Element(j))
+			}
+		}
+	})
+
+	b.Run("Pop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			for j := 0; j < elementsAmount; j++ {
+				stack.Pop()
+			}
+		}
+	})
+
+	b.Run("Push and pop in sequential order", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			for j := 0; j < elementsAmount; j++ {
+				stack.Push(factory.Element(j))
+				stack.Pop()
+			}
+		}
+	})
+
+	b.Run("Push and pop separately", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			for j := 0; j < elementsAmount; j++ {
+				stack.Push(factory.Element(j))
+			}
+			for j := 0; j < elementsAmount; j++ {
+				stack.Pop()
+			}
+		}
+	})
+
+	b.Run("Push and Pop in random order", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			for j := 0; j < elementsAmount; j++ {
+				operation := rand.Intn(2)
+				if operation == 0 {
+					stack.Push(factory.Element(j))
+				} else {
+					stack.Pop()
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkParallel[T comparable](b *testing.B, factory Factory[T]) {
+	// Metrics are measured for parallel operations.
+	elementsAmount := factory.elementsAmount()
+
+	b.Run("Push | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					stack.Push(factory.Element(j))
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Pop | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					stack.Pop()
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Push and pop in sequential order | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					stack.Push(factory.Element(j))
+					stack.Pop()
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	for _, gorutinesAmount := range []int{gorutinesAmount1, gorutinesAmount2} {
+		b.Run("Push and pop in sequential order | "+fmt.Sprintf("%d gorutines", gorutinesAmount), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				stack := factory.New()
+				wg := sync.WaitGroup{}
+				wg.Add(gorutinesAmount)
+				for j := 0; j < gorutinesAmount; j++ {
+					go func() {
+						defer wg.Done()
+						for j := 0; j < elementsAmount/gorutinesAmount; j++ {
+							stack.Push(factory.Element(j))
+							stack.Pop()
+						}
+					}()
+				}
+				wg.Wait()
+			}
+		})
+	}
+
+	b.Run("Push and pop in sequential order in different gorutines | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					stack.Push(factory.Element(j))
+				}()
+			}
+			wg.Wait()
+
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					stack.Pop()
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Push and Pop in random order | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stack := factory.New()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				if rand.Intn(2) == 0 {
+					go func() {
+						defer wg.Done()
+						stack.Push(factory.Element(j))
+					}()
+				} else {
+					go func() {
+						defer wg.Done()
+						stack.Pop()
+					}()
+				}
+			}
+			wg.Wait()
+		}
+	})
+
+	for _, gorutinesAmount := range []int{gorutinesAmount1, gorutinesAmount2} {
+		b.Run("Push and Pop in random order | "+fmt.Sprintf("%d gorutines", gorutinesAmount), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				stack := factory.New()
+				wg := sync.WaitGroup{}
+				wg.Add(gorutinesAmount)
+				for j := 0; j < gorutinesAmount; j++ {
+					if rand.Intn(2) == 0 {
+						go func() {
+							defer wg.Done()
+							for j := 0; j < elementsAmount/gorutinesAmount; j++ {
+								stack.Push(factory.Element(j))
+							}
+						}()
+					} else {
+						go func() {
+							defer wg.Done()
+							for j := 0; j < elementsAmount/gorutinesAmount; j++ {
+								stack.Pop()
+							}
+						}()
+					}
+				}
+				wg.Wait()
+			}
+		})
+	}
+}
+
+func checkEmptyStackError(t *testing.T, err error) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("Error: some value was received instead of the expected emptyStackError.")
+	} else if err.Error() != stacks.EmptyStackError {
+		t.Errorf("Error: received an error other than the expected emptyStackError.")
+	}
+}
+
+func checkNilPointerError(t *testing.T, method string, err error) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("Error: %s on a nil stack returned no error.", method)
+	} else if err.Error() != stacks.StackNilPointerError {
+		t.Errorf("Error: %s on a nil stack returned %q instead of the nilPointerError.", method, err.Error())
+	}
+}
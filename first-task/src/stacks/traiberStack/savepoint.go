@@ -0,0 +1,57 @@
+package traiberStack
+
+import (
+	"errors"
+	"src/stacks"
+)
+
+// Savepoints. A mark remembers the top cell at the moment of Mark. Cells never change after
+// they are published and a popped cell is never pushed again, so the marked cell is reachable
+// from the current top exactly when it has not been popped since: everything above it was pushed
+// after the mark. Then RewindTo discards those elements with a single CAS of the top.
+//
+// Concurrent poppers are not blocked. If one of them takes the marked element (or anything below it)
+// before RewindTo, the rewind fails with MarkInvalidatedError and leaves the stack unchanged.
+// If the elements above the mark are popped by others, the rewind simply discards fewer of them.
+
+type Mark[T any] struct {
+	stack *Stack[T] // The stack the mark was taken on.
+	top   *cell[T]  // The top at the moment of the mark, nil for the empty stack.
+}
+
+func (stack *Stack[T]) Mark() Mark[T] {
+	// Savepoint at the current top. The mark of a nil stack is never valid.
+	if stack == nil {
+		return Mark[T]{}
+	}
+	return Mark[T]{stack: stack, top: stack.top.Load()}
+}
+
+func (stack *Stack[T]) RewindTo(mark Mark[T]) (int, error) {
+	// Pops everything pushed after the mark in one step and returns how many elements were discarded.
+	if stack == nil {
+		return 0, errors.New(stacks.StackNilPointerError)
+	}
+	if mark.stack != stack {
+		return 0, errors.New(stacks.MarkInvalidatedError)
+	}
+	for {
+		oldTop := stack.top.Load()
+		discarded := 0
+		current := oldTop
+		for current != mark.top && current != nil {
+			discarded++
+			current = current.next.Load()
+		}
+		if current != mark.top {
+			// The bottom was reached before the marked cell.
+			return 0, errors.New(stacks.MarkInvalidatedError)
+		}
+		if stack.top.CompareAndSwap(oldTop, mark.top) {
+			return discarded, nil
+		}
+		if stack.observer != nil {
+			stack.observer.Event(stacks.PopOperation, stacks.CasRetry)
+		}
+	}
+}
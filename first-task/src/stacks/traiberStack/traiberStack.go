@@ -12,7 +12,8 @@ type cell[T any] struct {
 }
 
 type Stack[T any] struct {
-	top atomic.Pointer[cell[T]]
+	top      atomic.Pointer[cell[T]]
+	observer stacks.Observer // Optional, nil means that nobody watches the stack.
 }
 
 func FreshTraiberStack[T any]() *Stack[T] {
@@ -20,6 +21,11 @@ func FreshTraiberStack[T any]() *Stack[T] {
 	return &Stack[T]{}
 }
 
+func (stack *Stack[T]) SetObserver(observer stacks.Observer) {
+	// Must be called before the stack is shared between goroutines.
+	stack.observer = observer
+}
+
 func (stack *Stack[T]) Peek() (T, error) {
 
 	if stack == nil {
@@ -36,6 +42,9 @@ func (stack *Stack[T]) Push(value T) error {
 	if stack == nil {
 		return errors.New(stacks.StackNilPointerError)
 	}
+	if stack.observer != nil {
+		defer stack.observer.Begin(stacks.PushOperation)()
+	}
 	newTop := &cell[T]{value: value}
 	for {
 		oldTop := stack.top.Load()
@@ -43,10 +52,19 @@ func (stack *Stack[T]) Push(value T) error {
 		if stack.top.CompareAndSwap(oldTop, newTop) {
 			return nil
 		}
+		if stack.observer != nil {
+			stack.observer.Event(stacks.PushOperation, stacks.CasRetry)
+		}
 	}
 }
 
 func (stack *Stack[T]) Pop() (T, error) {
+	if stack == nil {
+		return *(new(T)), errors.New(stacks.StackNilPointerError)
+	}
+	if stack.observer != nil {
+		defer stack.observer.Begin(stacks.PopOperation)()
+	}
 	for {
 
 		oldTop := stack.top.Load()
@@ -57,6 +75,9 @@ func (stack *Stack[T]) Pop() (T, error) {
 		if stack.top.CompareAndSwap(oldTop, newTop) {
 			return oldTop.value, nil
 		}
+		if stack.observer != nil {
+			stack.observer.Event(stacks.PopOperation, stacks.CasRetry)
+		}
 	}
 }
 
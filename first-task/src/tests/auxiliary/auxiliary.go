@@ -1,10 +1,29 @@
 package auxiliary
 
 import (
+	"errors"
+	"src/bags"
+	"src/bags/concurrentBag"
+	"src/counters"
+	"src/counters/approximateCounter"
+	"src/counters/combiningTreeCounter"
+	"src/counters/shardedCounter"
+	"src/deques"
+	"src/deques/anchorDeque"
+	"src/deques/consistentDeque"
+	"src/deques/twoLockDeque"
+	"src/pools"
+	"src/pools/channelPool"
+	"src/pools/workStealingPool"
+	"src/queues"
+	"src/queues/ringBuffer"
 	"src/stacks"
 	"src/stacks/consistentStack"
 	"src/stacks/optimizedTraiberStack"
+	"src/stacks/persistentStack"
+	"src/stacks/stackChannels"
 	"src/stacks/traiberStack"
+	"sync/atomic"
 )
 
 // Helper functions that resolve type problems in a tests and benchmarks.
@@ -20,3 +39,181 @@ func FreshTraiberStack() stacks.Stack[int] {
 func FreshOptimizedTraiberStack() stacks.Stack[int] {
 	return optimizedTraiberStack.FreshOptimizedTraiberStack[int]()
 }
+
+func FreshVersionedStack() stacks.Stack[int] {
+	return persistentStack.FreshVersionedStack[int]()
+}
+
+func FreshChannelStack() stacks.Stack[int] {
+	// Without a source channel it is a plain concurrent stack.
+	return stackChannels.FreshChannelStack[int](nil)
+}
+
+func FreshWorkStealingPool() pools.Pool {
+	return workStealingPool.FreshWorkStealingPool(0)
+}
+
+func FreshChannelPool() pools.Pool {
+	return channelPool.FreshChannelPool(0, channelPoolCapacity)
+}
+
+// The channel pool blocks Submit on a full channel, so fork-join tasks need enough room
+// to never wait for a worker that is itself waiting in Submit.
+const channelPoolCapacity = 1 << 16
+
+// Containers used as bags, when the order of elements does not matter.
+
+type ringBufferBag struct {
+	buffer *ringBuffer.RingBuffer[int]
+}
+
+func (bag ringBufferBag) Add(value int) error {
+	return bag.buffer.TryEnqueue(value)
+}
+
+func (bag ringBufferBag) TryTake() (int, error) {
+	return bag.buffer.TryDequeue()
+}
+
+type stackBag struct {
+	stack stacks.Stack[int]
+}
+
+func (bag stackBag) Add(value int) error {
+	return bag.stack.Push(value)
+}
+
+func (bag stackBag) TryTake() (int, error) {
+	return bag.stack.Pop()
+}
+
+func FreshRingBuffer() queues.BoundedQueue[int] {
+	return ringBuffer.FreshRingBuffer[int](ringBufferCapacity)
+}
+
+func FreshRingBufferBag() bags.Bag[int] {
+	return ringBufferBag{buffer: ringBuffer.FreshRingBuffer[int](ringBufferCapacity)}
+}
+
+func FreshTraiberStackBag() bags.Bag[int] {
+	return stackBag{stack: FreshTraiberStack()}
+}
+
+func FreshConcurrentBag() bags.Bag[int] {
+	return concurrentBag.FreshConcurrentBag[int]()
+}
+
+// Large enough to hold all the elements of any test or benchmark scenario.
+const ringBufferCapacity = 1 << 20
+
+func FreshConsistentDeque() deques.Deque[int] {
+	return consistentDeque.FreshConsistentDeque[int]()
+}
+
+func FreshTwoLockDeque() deques.Deque[int] {
+	return twoLockDeque.FreshTwoLockDeque[int]()
+}
+
+func FreshAnchorDeque() deques.Deque[int] {
+	return anchorDeque.FreshAnchorDeque[int]()
+}
+
+// The deques used as stacks at one of their ends, so that the stack tests and benchmarks apply to them.
+
+type dequeFront struct {
+	deque deques.Deque[int]
+}
+
+func (stack dequeFront) Push(value int) error {
+	return stack.deque.PushFront(value)
+}
+
+func (stack dequeFront) Pop() (int, error) {
+	return asStackError(stack.deque.PopFront())
+}
+
+func (stack dequeFront) Peek() (int, error) {
+	return asStackError(stack.deque.PeekFront())
+}
+
+func (stack dequeFront) Len() (int, error) {
+	return stack.deque.Len()
+}
+
+type dequeBack struct {
+	deque deques.Deque[int]
+}
+
+func (stack dequeBack) Push(value int) error {
+	return stack.deque.PushBack(value)
+}
+
+func (stack dequeBack) Pop() (int, error) {
+	return asStackError(stack.deque.PopBack())
+}
+
+func (stack dequeBack) Peek() (int, error) {
+	return asStackError(stack.deque.PeekBack())
+}
+
+func (stack dequeBack) Len() (int, error) {
+	return stack.deque.Len()
+}
+
+func asStackError(value int, err error) (int, error) {
+	if err != nil && err.Error() == deques.EmptyDequeError {
+		return value, errors.New(stacks.EmptyStackError)
+	}
+	return value, err
+}
+
+func FreshTwoLockDequeFront() stacks.Stack[int] {
+	return dequeFront{deque: FreshTwoLockDeque()}
+}
+
+func FreshTwoLockDequeBack() stacks.Stack[int] {
+	return dequeBack{deque: FreshTwoLockDeque()}
+}
+
+func FreshAnchorDequeFront() stacks.Stack[int] {
+	return dequeFront{deque: FreshAnchorDeque()}
+}
+
+func FreshAnchorDequeBack() stacks.Stack[int] {
+	return dequeBack{deque: FreshAnchorDeque()}
+}
+
+// The plain atomic counter that the scalable counters are compared with.
+type atomicCounter struct {
+	value atomic.Int64
+}
+
+func (counter *atomicCounter) Add(delta int64) {
+	counter.value.Add(delta)
+}
+
+func (counter *atomicCounter) Load() int64 {
+	return counter.value.Load()
+}
+
+func (counter *atomicCounter) Reset() {
+	counter.value.Store(0)
+}
+
+func FreshAtomicCounter() counters.Counter {
+	return &atomicCounter{}
+}
+
+func FreshShardedCounter() counters.Counter {
+	return shardedCounter.FreshShardedCounter()
+}
+
+func FreshApproximateCounter() counters.Counter {
+	return approximateCounter.FreshApproximateCounter(approximateCounterThreshold)
+}
+
+func FreshCombiningTreeCounter() counters.Counter {
+	return combiningTreeCounter.FreshCombiningTreeCounter()
+}
+
+const approximateCounterThreshold = 64
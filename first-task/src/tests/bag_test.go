@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"src/bags"
+	"src/bags/concurrentBag"
+	"sync"
+	"testing"
+)
+
+// In these test cases we check that the bag neither loses nor duplicates elements.
+
+func TestConcurrentBag(t *testing.T) {
+
+	t.Run("Test empty bag take", func(t *testing.T) {
+		bag := concurrentBag.FreshConcurrentBag[int]()
+		elem, err := bag.TryTake()
+		if err == nil {
+			t.Errorf("Error: value %d was received instead of the expected emptyBagError.", elem)
+		} else if err.Error() != bags.EmptyBagError {
+			t.Errorf("Error: received an error other than the expected emptyBagError.")
+		}
+	})
+
+	t.Run("Test take steals from other buffers", func(t *testing.T) {
+		// Everything is added through one handle and must be reachable through another one.
+		bag := concurrentBag.FreshConcurrentBag[int]()
+		producer, consumer := bag.Local(), bag.Local()
+		for i := 0; i < 100; i++ {
+			producer.Add(i)
+		}
+		for i := 0; i < 100; i++ {
+			if _, err := consumer.TryTake(); err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+		}
+		if bagLen, _ := bag.Len(); bagLen != 0 {
+			t.Errorf("Received bag size %d != expected bag size 0", bagLen)
+		}
+	})
+
+	t.Run("Test conservation", func(t *testing.T) {
+		// Half of the goroutines add unique values, the other half take them,
+		// then every value must be seen exactly once among the taken and the remaining ones.
+		const gorutinesAmount = 16
+		const perGorutine = tasksAmount / gorutinesAmount
+		bag := concurrentBag.FreshConcurrentBag[int]()
+		taken := make([][]int, gorutinesAmount)
+
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for i := 0; i < gorutinesAmount; i++ {
+			go func() {
+				defer wg.Done()
+				local := bag.Local()
+				for j := 0; j < perGorutine; j++ {
+					if i%2 == 0 {
+						if err := local.Add(i*perGorutine + j); err != nil {
+							t.Errorf("Unexpected error: %s", err.Error())
+						}
+					} else if elem, err := local.TryTake(); err == nil {
+						taken[i] = append(taken[i], elem)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		seen := make([]int, gorutinesAmount*perGorutine)
+		for _, values := range taken {
+			for _, value := range values {
+				seen[value]++
+			}
+		}
+		for {
+			elem, err := bag.TryTake()
+			if err != nil {
+				break
+			}
+			seen[elem]++
+		}
+		for value, times := range seen {
+			added := (value/perGorutine)%2 == 0
+			if added && times != 1 || !added && times != 0 {
+				t.Fatalf("Value %d was seen %d times", value, times)
+			}
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package benchmarks
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"src/bags"
+	"src/bags/concurrentBag"
+	"src/tests/auxiliary"
+	"sync"
+	"testing"
+)
+
+// Metrics are measured for parallel operations with containers used as bags,
+// so only the throughput matters and not the order of elements.
+
+func BenchmarkParallelRingBuffer(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runParallelBagBenchmarks(b, auxiliary.FreshRingBufferBag)
+}
+
+func BenchmarkParallelTraiberStackBag(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runParallelBagBenchmarks(b, auxiliary.FreshTraiberStackBag)
+}
+
+func BenchmarkParallelConcurrentBag(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runParallelBagBenchmarks(b, auxiliary.FreshConcurrentBag)
+}
+
+func runParallelBagBenchmarks(b *testing.B, newBag func() bags.Bag[int]) {
+
+	b.Run("Add | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bag := newBag()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					bag.Add(j)
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Take | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bag := newBag()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					bag.TryTake()
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add and take in sequential order | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bag := newBag()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					bag.Add(j)
+					bag.TryTake()
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add and take in sequential order | 8 gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bag := newBag()
+			wg := sync.WaitGroup{}
+			wg.Add(gorutinesAmount1)
+			for j := 0; j < gorutinesAmount1; j++ {
+				go func() {
+					defer wg.Done()
+					for j := 0; j < elementsAmount/gorutinesAmount1; j++ {
+						bag.Add(j)
+						bag.TryTake()
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add and take in sequential order | 100 gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bag := newBag()
+			wg := sync.WaitGroup{}
+			wg.Add(gorutinesAmount2)
+			for j := 0; j < gorutinesAmount2; j++ {
+				go func() {
+					defer wg.Done()
+					for j := 0; j < elementsAmount/gorutinesAmount2; j++ {
+						bag.Add(j)
+						bag.TryTake()
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add and take in sequential order in different gorutines | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bag := newBag()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					bag.Add(j)
+				}()
+			}
+			wg.Wait()
+
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					bag.TryTake()
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add and take in random order | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bag := newBag()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				operation := rand.Intn(2)
+				if operation == 0 {
+					go func() {
+						defer wg.Done()
+						bag.Add(j)
+					}()
+				} else {
+					go func() {
+						defer wg.Done()
+						bag.TryTake()
+					}()
+				}
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add and take in random order | 8 gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bag := newBag()
+			wg := sync.WaitGroup{}
+			wg.Add(gorutinesAmount1)
+			for j := 0; j < gorutinesAmount1; j++ {
+				if rand.Intn(2) == 0 {
+					go func() {
+						defer wg.Done()
+						for j := 0; j < elementsAmount/gorutinesAmount1; j++ {
+							bag.Add(j)
+						}
+					}()
+				} else {
+					go func() {
+						defer wg.Done()
+						for j := 0; j < elementsAmount/gorutinesAmount1; j++ {
+							bag.TryTake()
+						}
+					}()
+				}
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add and take in random order | 100 gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bag := newBag()
+			wg := sync.WaitGroup{}
+			wg.Add(gorutinesAmount2)
+			for j := 0; j < gorutinesAmount2; j++ {
+				if rand.Intn(2) == 0 {
+					go func() {
+						defer wg.Done()
+						for j := 0; j < elementsAmount/gorutinesAmount2; j++ {
+							bag.Add(j)
+						}
+					}()
+				} else {
+					go func() {
+						defer wg.Done()
+						for j := 0; j < elementsAmount/gorutinesAmount2; j++ {
+							bag.TryTake()
+						}
+					}()
+				}
+			}
+			wg.Wait()
+		}
+	})
+}
+
+func BenchmarkParallelConcurrentBagLocal(b *testing.B) {
+	// Every goroutine works through its own handle, so it mostly stays in its own buffer.
+	runtime.GOMAXPROCS(16)
+	for _, gorutinesAmount := range []int{gorutinesAmount1, gorutinesAmount2} {
+		b.Run(fmt.Sprintf("Add and take in sequential order | %d gorutines", gorutinesAmount), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bag := concurrentBag.FreshConcurrentBag[int]()
+				wg := sync.WaitGroup{}
+				wg.Add(gorutinesAmount)
+				for j := 0; j < gorutinesAmount; j++ {
+					go func() {
+						defer wg.Done()
+						local := bag.Local()
+						for j := 0; j < elementsAmount/gorutinesAmount; j++ {
+							local.Add(j)
+							local.TryTake()
+						}
+					}()
+				}
+				wg.Wait()
+			}
+		})
+	}
+}
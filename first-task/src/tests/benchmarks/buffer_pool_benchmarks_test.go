@@ -0,0 +1,45 @@
+package benchmarks
+
+import (
+	"fmt"
+	"runtime"
+	"src/bufferPool"
+	"sync"
+	"testing"
+)
+
+// Metrics are measured for the buffer pool and for sync.Pool with a pool per buffer size.
+
+var bufferSizes = []int{64, 1 << 10, 1 << 14, 1 << 18}
+
+func BenchmarkParallelBufferPool(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	pool := bufferPool.FreshBufferPool(1024)
+	for _, size := range bufferSizes {
+		b.Run(fmt.Sprintf("Get and put | %d bytes", size), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					buffer := pool.Get(size)
+					buffer[0] = 1
+					pool.Put(buffer)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkParallelSyncPool(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	for _, size := range bufferSizes {
+		pool := sync.Pool{New: func() any { return make([]byte, size) }}
+		b.Run(fmt.Sprintf("Get and put | %d bytes", size), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					buffer := pool.Get().([]byte)
+					buffer[0] = 1
+					pool.Put(buffer)
+				}
+			})
+		})
+	}
+}
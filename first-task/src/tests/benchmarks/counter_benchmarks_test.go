@@ -0,0 +1,126 @@
+package benchmarks
+
+import (
+	"runtime"
+	"src/counters"
+	"src/tests/auxiliary"
+	"sync"
+	"testing"
+)
+
+// Metrics are measured for parallel additions to the scalable counters and to a plain atomic one.
+
+func BenchmarkParallelAtomicCounter(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runCounterBenchmarks(b, auxiliary.FreshAtomicCounter)
+}
+
+func BenchmarkParallelShardedCounter(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runCounterBenchmarks(b, auxiliary.FreshShardedCounter)
+}
+
+func BenchmarkParallelApproximateCounter(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runCounterBenchmarks(b, auxiliary.FreshApproximateCounter)
+}
+
+func BenchmarkParallelCombiningTreeCounter(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runCounterBenchmarks(b, auxiliary.FreshCombiningTreeCounter)
+}
+
+func runCounterBenchmarks(b *testing.B, newCounter func() counters.Counter) {
+
+	b.Run("Add | All gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			counter := newCounter()
+			wg := sync.WaitGroup{}
+			wg.Add(elementsAmount)
+			for j := 0; j < elementsAmount; j++ {
+				go func() {
+					defer wg.Done()
+					counter.Add(1)
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add | 8 gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			counter := newCounter()
+			wg := sync.WaitGroup{}
+			wg.Add(gorutinesAmount1)
+			for j := 0; j < gorutinesAmount1; j++ {
+				go func() {
+					defer wg.Done()
+					for j := 0; j < elementsAmount/gorutinesAmount1; j++ {
+						counter.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add | 100 gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			counter := newCounter()
+			wg := sync.WaitGroup{}
+			wg.Add(gorutinesAmount2)
+			for j := 0; j < gorutinesAmount2; j++ {
+				go func() {
+					defer wg.Done()
+					for j := 0; j < elementsAmount/gorutinesAmount2; j++ {
+						counter.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add and load | 8 gorutines", func(b *testing.B) {
+		// Every hundredth operation reads the counter.
+		for i := 0; i < b.N; i++ {
+			counter := newCounter()
+			wg := sync.WaitGroup{}
+			wg.Add(gorutinesAmount1)
+			for j := 0; j < gorutinesAmount1; j++ {
+				go func() {
+					defer wg.Done()
+					for j := 0; j < elementsAmount/gorutinesAmount1; j++ {
+						if j%100 == 0 {
+							counter.Load()
+						} else {
+							counter.Add(1)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Add and load | 100 gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			counter := newCounter()
+			wg := sync.WaitGroup{}
+			wg.Add(gorutinesAmount2)
+			for j := 0; j < gorutinesAmount2; j++ {
+				go func() {
+					defer wg.Done()
+					for j := 0; j < elementsAmount/gorutinesAmount2; j++ {
+						if j%100 == 0 {
+							counter.Load()
+						} else {
+							counter.Add(1)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package benchmarks
+
+import (
+	"runtime"
+	"src/tests/auxiliary"
+	"testing"
+)
+
+// Metrics are measured for the deques used as stacks at each of their ends,
+// the scenarios are the same as for the stacks.
+
+func BenchmarkSequentialTwoLockDequeFront(b *testing.B) {
+	runsSequentialBenchmarks(b, auxiliary.FreshTwoLockDequeFront)
+}
+
+func BenchmarkSequentialTwoLockDequeBack(b *testing.B) {
+	runsSequentialBenchmarks(b, auxiliary.FreshTwoLockDequeBack)
+}
+
+func BenchmarkSequentialAnchorDequeFront(b *testing.B) {
+	runsSequentialBenchmarks(b, auxiliary.FreshAnchorDequeFront)
+}
+
+func BenchmarkSequentialAnchorDequeBack(b *testing.B) {
+	runsSequentialBenchmarks(b, auxiliary.FreshAnchorDequeBack)
+}
+
+func BenchmarkParallelTwoLockDequeFront(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runParallelBenchmarks(b, auxiliary.FreshTwoLockDequeFront)
+}
+
+func BenchmarkParallelTwoLockDequeBack(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runParallelBenchmarks(b, auxiliary.FreshTwoLockDequeBack)
+}
+
+func BenchmarkParallelAnchorDequeFront(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runParallelBenchmarks(b, auxiliary.FreshAnchorDequeFront)
+}
+
+func BenchmarkParallelAnchorDequeBack(b *testing.B) {
+	runtime.GOMAXPROCS(16)
+	runParallelBenchmarks(b, auxiliary.FreshAnchorDequeBack)
+}
@@ -0,0 +1,146 @@
+package benchmarks
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"src/pools"
+	"src/pools/workStealingPool"
+	"src/tests/auxiliary"
+	"testing"
+)
+
+// Metrics are measured for fork-join workloads executed by the goroutine pools.
+
+func BenchmarkWorkStealingPool(b *testing.B) {
+	runPoolBenchmarks(b, auxiliary.FreshWorkStealingPool)
+
+	b.Run("Parallel quicksort | Worker-local submits", func(b *testing.B) {
+		pool := workStealingPool.FreshWorkStealingPool(0)
+		defer pool.Shutdown(context.Background())
+		source := rand.Perm(elementsAmount)
+		data := make([]int, elementsAmount)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			copy(data, source)
+			b.StartTimer()
+
+			pool.Spawn(func(worker *workStealingPool.Worker) { localQuicksort(worker, data) })
+			pool.Wait()
+		}
+		b.StopTimer()
+
+		if !slices.IsSorted(data) {
+			b.Errorf("Error: the array is not sorted.")
+		}
+	})
+}
+
+func BenchmarkChannelPool(b *testing.B) {
+	runPoolBenchmarks(b, auxiliary.FreshChannelPool)
+}
+
+const (
+	sortCutoff     = 2048 // Subarrays of at most this length are sorted sequentially.
+	fibonacciDepth = 25
+	fibonacciLimit = 12 // Fibonacci numbers with a smaller argument are computed sequentially.
+)
+
+func runPoolBenchmarks(b *testing.B, newPool func() pools.Pool) {
+
+	b.Run("Parallel quicksort", func(b *testing.B) {
+		pool := newPool()
+		defer pool.Shutdown(context.Background())
+		source := rand.Perm(elementsAmount)
+		data := make([]int, elementsAmount)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			copy(data, source)
+			b.StartTimer()
+
+			pool.Submit(func() { quicksort(pool, data) })
+			pool.Wait()
+		}
+		b.StopTimer()
+
+		if !slices.IsSorted(data) {
+			b.Errorf("Error: the array is not sorted.")
+		}
+	})
+
+	b.Run("Recursive fibonacci", func(b *testing.B) {
+		pool := newPool()
+		defer pool.Shutdown(context.Background())
+
+		for i := 0; i < b.N; i++ {
+			pool.Submit(func() { fibonacci(pool, fibonacciDepth) })
+			pool.Wait()
+		}
+	})
+
+	b.Run("Independent small tasks", func(b *testing.B) {
+		pool := newPool()
+		defer pool.Shutdown(context.Background())
+
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < elementsAmount/100; j++ {
+				pool.Submit(func() { fibonacci(nil, fibonacciLimit) })
+			}
+			pool.Wait()
+		}
+	})
+}
+
+func quicksort(pool pools.Pool, data []int) {
+	if len(data) <= sortCutoff {
+		slices.Sort(data)
+		return
+	}
+	pivot := partition(data)
+	pool.Submit(func() { quicksort(pool, data[:pivot]) })
+	pool.Submit(func() { quicksort(pool, data[pivot+1:]) })
+}
+
+func localQuicksort(worker *workStealingPool.Worker, data []int) {
+	// The same as quicksort, but the halves go into the deque of the current worker.
+	if len(data) <= sortCutoff {
+		slices.Sort(data)
+		return
+	}
+	pivot := partition(data)
+	worker.Submit(func(worker *workStealingPool.Worker) { localQuicksort(worker, data[:pivot]) })
+	worker.Submit(func(worker *workStealingPool.Worker) { localQuicksort(worker, data[pivot+1:]) })
+}
+
+func partition(data []int) int {
+	// Lomuto partition around the middle element.
+	middle := len(data) / 2
+	last := len(data) - 1
+	data[middle], data[last] = data[last], data[middle]
+	store := 0
+	for i := 0; i < last; i++ {
+		if data[i] < data[last] {
+			data[i], data[store] = data[store], data[i]
+			store++
+		}
+	}
+	data[store], data[last] = data[last], data[store]
+	return store
+}
+
+func fibonacci(pool pools.Pool, n int) int {
+	// With a pool only the tree of tasks is spawned, the result itself is not collected.
+	if n < 2 {
+		return n
+	}
+	if pool == nil || n < fibonacciLimit {
+		return fibonacci(nil, n-1) + fibonacci(nil, n-2)
+	}
+	pool.Submit(func() { fibonacci(pool, n-1) })
+	pool.Submit(func() { fibonacci(pool, n-2) })
+	return 0
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"runtime"
+	"src/bufferPool"
+	"sync"
+	"testing"
+)
+
+// In these test cases we check size classes, limits and statistics of the buffer pool.
+
+func TestBufferPool(t *testing.T) {
+
+	t.Run("Test size classes", func(t *testing.T) {
+		pool := bufferPool.FreshBufferPool(4)
+		for _, sizes := range [][2]int{{0, 64}, {1, 64}, {64, 64}, {65, 128}, {1000, 1024}, {1 << 20, 1 << 20}} {
+			buffer := pool.Get(sizes[0])
+			if len(buffer) != sizes[0] || cap(buffer) != sizes[1] {
+				t.Errorf("Received buffer len %d, cap %d != expected buffer len %d, cap %d", len(buffer), cap(buffer), sizes[0], sizes[1])
+			}
+		}
+		if buffer := pool.Get(1<<20 + 1); len(buffer) != 1<<20+1 {
+			t.Errorf("Received buffer len %d != expected buffer len %d", len(buffer), 1<<20+1)
+		}
+	})
+
+	t.Run("Test reuse survives garbage collection", func(t *testing.T) {
+		pool := bufferPool.FreshBufferPool(4)
+		buffer := pool.Get(100)
+		pool.Put(buffer)
+		runtime.GC()
+		runtime.GC()
+
+		reused := pool.Get(120)
+		if &reused[:1][0] != &buffer[:1][0] {
+			t.Errorf("Error: the pool allocated a new buffer instead of reusing the returned one.")
+		}
+		if stats := pool.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Puts != 1 {
+			t.Errorf("Received stats %+v != expected 1 hit, 1 miss and 1 put", stats)
+		}
+	})
+
+	t.Run("Test capacity limit", func(t *testing.T) {
+		pool := bufferPool.FreshBufferPool(2)
+		buffers := [][]byte{pool.Get(64), pool.Get(64), pool.Get(64)}
+		for _, buffer := range buffers {
+			pool.Put(buffer)
+		}
+		pool.Put(make([]byte, 100)) // Not a size of any class.
+
+		stats := pool.Stats()
+		if stats.Puts != 2 || stats.Dropped != 2 {
+			t.Errorf("Received stats %+v != expected 2 puts and 2 dropped", stats)
+		}
+		pool.Get(64)
+		pool.Get(64)
+		pool.Get(64)
+		if stats := pool.Stats(); stats.HitRate() != 2.0/6.0 {
+			t.Errorf("Received hit rate %f != expected hit rate %f", stats.HitRate(), 2.0/6.0)
+		}
+	})
+
+	t.Run("Test parallel get and put", func(t *testing.T) {
+		// A buffer must never be handed to two goroutines at once:
+		// every goroutine fills its buffer with its own number and checks it before returning it.
+		const gorutinesAmount = 16
+		pool := bufferPool.FreshBufferPool(8)
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for g := 0; g < gorutinesAmount; g++ {
+			go func() {
+				defer wg.Done()
+				for i := 0; i < tasksAmount/gorutinesAmount; i++ {
+					buffer := pool.Get(64 << (i % 4))
+					for j := range buffer {
+						buffer[j] = byte(g)
+					}
+					runtime.Gosched()
+					for j := range buffer {
+						if buffer[j] != byte(g) {
+							t.Errorf("Error: the buffer was shared with another goroutine.")
+							return
+						}
+					}
+					pool.Put(buffer)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if stats := pool.Stats(); stats.Hits+stats.Misses != tasksAmount {
+			t.Errorf("Received gets %d != expected gets %d", stats.Hits+stats.Misses, tasksAmount)
+		}
+	})
+
+	t.Run("Test nil pool", func(t *testing.T) {
+		var pool *bufferPool.Pool
+		if buffer := pool.Get(100); len(buffer) != 100 {
+			t.Errorf("Received buffer len %d != expected buffer len 100", len(buffer))
+		}
+		pool.Put(make([]byte, 128))
+		if stats := pool.Stats(); stats != (bufferPool.Stats{}) {
+			t.Errorf("Received stats %+v != expected zero stats", stats)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"src/counters"
+	"src/counters/approximateCounter"
+	"src/counters/combiningTreeCounter"
+	"src/counters/shardedCounter"
+	"src/tests/auxiliary"
+	"sync"
+	"testing"
+)
+
+// In these test cases we check that the scalable counters do not lose additions.
+
+func TestShardedCounter(t *testing.T) {
+	runCounterTests(t, auxiliary.FreshShardedCounter)
+}
+
+func TestCombiningTreeCounter(t *testing.T) {
+	runCounterTests(t, auxiliary.FreshCombiningTreeCounter)
+}
+
+func TestApproximateCounter(t *testing.T) {
+
+	t.Run("Test error bound", func(t *testing.T) {
+		counter := approximateCounter.FreshApproximateCounter(16)
+		addInParallel(counter, 8)
+
+		difference := int64(tasksAmount) - counter.Load()
+		if difference < 0 || difference > counter.ErrorBound() {
+			t.Errorf("Received value %d differs from expected value %d by more than %d", counter.Load(), tasksAmount, counter.ErrorBound())
+		}
+	})
+
+	t.Run("Test threshold one is exact", func(t *testing.T) {
+		counter := approximateCounter.FreshApproximateCounter(1)
+		addInParallel(counter, 8)
+		if counter.Load() != tasksAmount {
+			t.Errorf("Received value %d != expected value %d", counter.Load(), tasksAmount)
+		}
+	})
+}
+
+func TestNilCounters(t *testing.T) {
+	for name, counter := range map[string]counters.Counter{
+		"Sharded counter":        (*shardedCounter.Counter)(nil),
+		"Combining tree counter": (*combiningTreeCounter.Counter)(nil),
+		"Approximate counter":    (*approximateCounter.Counter)(nil),
+	} {
+		counter.Add(1)
+		counter.Reset()
+		if counter.Load() != 0 {
+			t.Errorf("%s: received value %d != expected value 0", name, counter.Load())
+		}
+	}
+}
+
+func runCounterTests(t *testing.T, newCounter func() counters.Counter) {
+
+	t.Run("Test sequential add", func(t *testing.T) {
+		counter := newCounter()
+		for i := 0; i < 100; i++ {
+			counter.Add(2)
+		}
+		counter.Add(-50)
+		if counter.Load() != 150 {
+			t.Errorf("Received value %d != expected value 150", counter.Load())
+		}
+	})
+
+	t.Run("Test parallel add", func(t *testing.T) {
+		for _, gorutinesAmount := range []int{8, 100, tasksAmount} {
+			counter := newCounter()
+			addInParallel(counter, gorutinesAmount)
+			if counter.Load() != tasksAmount {
+				t.Errorf("Received value %d != expected value %d with %d gorutines", counter.Load(), tasksAmount, gorutinesAmount)
+			}
+		}
+	})
+
+	t.Run("Test reset", func(t *testing.T) {
+		counter := newCounter()
+		addInParallel(counter, 8)
+		counter.Reset()
+		if counter.Load() != 0 {
+			t.Errorf("Received value %d != expected value 0", counter.Load())
+		}
+		counter.Add(5)
+		if counter.Load() != 5 {
+			t.Errorf("Received value %d != expected value 5", counter.Load())
+		}
+	})
+}
+
+func addInParallel(counter counters.Counter, gorutinesAmount int) {
+	// Adds one tasksAmount times in total, spread over the goroutines.
+	wg := sync.WaitGroup{}
+	wg.Add(gorutinesAmount)
+	for i := 0; i < gorutinesAmount; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < tasksAmount/gorutinesAmount; j++ {
+				counter.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+}
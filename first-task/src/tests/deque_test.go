@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"math/rand"
+	"runtime"
+	"src/deques"
+	"src/stacks"
+	"src/tests/auxiliary"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// In these test cases we compare the concurrent deques with the sequential one
+// and check that no element is lost or duplicated under parallel access.
+
+func TestTwoLockDeque(t *testing.T) {
+	runDequeTests(t, auxiliary.FreshTwoLockDeque)
+}
+
+func TestAnchorDeque(t *testing.T) {
+	runDequeTests(t, auxiliary.FreshAnchorDeque)
+}
+
+func TestDequeEndsAsStacks(t *testing.T) {
+	// Every end of a deque on its own must behave exactly like a stack.
+	for name, newStack := range map[string]func() stacks.Stack[int]{
+		"Two-lock deque front": auxiliary.FreshTwoLockDequeFront,
+		"Two-lock deque back":  auxiliary.FreshTwoLockDequeBack,
+		"Anchor deque front":   auxiliary.FreshAnchorDequeFront,
+		"Anchor deque back":    auxiliary.FreshAnchorDequeBack,
+	} {
+		t.Run(name, func(t *testing.T) {
+			runStackTests(t, newStack)
+		})
+	}
+}
+
+const dequeOperationsAmount = 100_000
+
+func runDequeTests(t *testing.T, newDeque func() deques.Deque[int]) {
+
+	t.Run("Test empty deque", func(t *testing.T) {
+		deque := newDeque()
+		for _, operation := range []func() (int, error){deque.PopFront, deque.PopBack, deque.PeekFront, deque.PeekBack} {
+			elem, err := operation()
+			if err == nil {
+				t.Errorf("Error: value %d was received instead of the expected emptyDequeError.", elem)
+			} else if err.Error() != deques.EmptyDequeError {
+				t.Errorf("Error: received an error other than the expected emptyDequeError.")
+			}
+		}
+	})
+
+	t.Run("Test push at one end and pop at the other", func(t *testing.T) {
+		deque := newDeque()
+		for i := 0; i < 100; i++ {
+			deque.PushBack(i)
+		}
+		for i := 0; i < 100; i++ {
+			elem, err := deque.PopFront()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			if elem != i {
+				t.Fatalf("Received removed element %d != expected removed element %d", elem, i)
+			}
+		}
+	})
+
+	t.Run("Test differential with sequential deque", func(t *testing.T) {
+		// The same random sequence of operations is applied to the sequential deque and to the tested one,
+		// every result and every error must coincide.
+		model := auxiliary.FreshConsistentDeque()
+		deque := newDeque()
+		random := rand.New(rand.NewSource(1))
+
+		for i := 0; i < dequeOperationsAmount; i++ {
+			var expected, received int
+			var expectedErr, receivedErr error
+			operation := random.Intn(7)
+			switch operation {
+			case 0:
+				expectedErr, receivedErr = model.PushFront(i), deque.PushFront(i)
+			case 1:
+				expectedErr, receivedErr = model.PushBack(i), deque.PushBack(i)
+			case 2:
+				expected, expectedErr = model.PopFront()
+				received, receivedErr = deque.PopFront()
+			case 3:
+				expected, expectedErr = model.PopBack()
+				received, receivedErr = deque.PopBack()
+			case 4:
+				expected, expectedErr = model.PeekFront()
+				received, receivedErr = deque.PeekFront()
+			case 5:
+				expected, expectedErr = model.PeekBack()
+				received, receivedErr = deque.PeekBack()
+			default:
+				expected, expectedErr = model.Len()
+				received, receivedErr = deque.Len()
+			}
+
+			if (expectedErr == nil) != (receivedErr == nil) {
+				t.Fatalf("Operation %d (kind %d): received error %v != expected error %v", i, operation, receivedErr, expectedErr)
+			}
+			if expectedErr != nil && expectedErr.Error() != receivedErr.Error() {
+				t.Fatalf("Operation %d (kind %d): received error %v != expected error %v", i, operation, receivedErr, expectedErr)
+			}
+			if received != expected {
+				t.Fatalf("Operation %d (kind %d): received %d != expected %d", i, operation, received, expected)
+			}
+		}
+	})
+
+	t.Run("Test parallel push and pop at both ends", func(t *testing.T) {
+		// Every pushed element is popped at most once, the rest stays in the deque.
+		const gorutinesAmount = 8
+		deque := newDeque()
+		pushed := atomic.Int64{}
+		popped := atomic.Int64{}
+
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for i := 0; i < gorutinesAmount; i++ {
+			go func() {
+				defer wg.Done()
+				random := rand.New(rand.NewSource(int64(i)))
+				for j := 1; j <= dequeOperationsAmount/gorutinesAmount; j++ {
+					switch random.Intn(4) {
+					case 0:
+						deque.PushFront(j)
+						pushed.Add(int64(j))
+					case 1:
+						deque.PushBack(j)
+						pushed.Add(int64(j))
+					case 2:
+						if elem, err := deque.PopFront(); err == nil {
+							popped.Add(int64(elem))
+						}
+					default:
+						if elem, err := deque.PopBack(); err == nil {
+							popped.Add(int64(elem))
+						}
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		for {
+			elem, err := deque.PopBack()
+			if err != nil {
+				break
+			}
+			popped.Add(int64(elem))
+		}
+		if pushed.Load() != popped.Load() {
+			t.Errorf("Received sum of popped elements %d != expected sum of pushed elements %d", popped.Load(), pushed.Load())
+		}
+		if dequeLen, _ := deque.Len(); dequeLen != 0 {
+			t.Errorf("Received deque size %d != expected deque size 0", dequeLen)
+		}
+	})
+
+	t.Run("Test parallel order at each end", func(t *testing.T) {
+		// One goroutine feeds the back, another drains the front: the elements must arrive in FIFO order.
+		deque := newDeque()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < dequeOperationsAmount; i++ {
+				deque.PushBack(i)
+			}
+		}()
+
+		next := 0
+		for next < dequeOperationsAmount {
+			elem, err := deque.PopFront()
+			if err != nil {
+				continue
+			}
+			if elem != next {
+				t.Fatalf("Received removed element %d != expected removed element %d", elem, next)
+			}
+			next++
+		}
+		<-done
+	})
+
+	t.Run("Test popped elements are released", func(t *testing.T) {
+		// The deque always holds one element, so a popped node that stays linked to it is never collected.
+		const rounds = 1 << 20
+		const limit = 8 << 20
+		for name, round := range map[string]func(deques.Deque[int]){
+			"Push back, pop front": func(deque deques.Deque[int]) { deque.PushBack(0); deque.PopFront() },
+			"Push front, pop back": func(deque deques.Deque[int]) { deque.PushFront(0); deque.PopBack() },
+		} {
+			deque := newDeque()
+			deque.PushBack(0)
+			before := heapInuse()
+			for i := 0; i < rounds; i++ {
+				round(deque)
+			}
+			if grown := int64(heapInuse()) - int64(before); grown > limit {
+				t.Errorf("%s: the heap grew by %d bytes after %d rounds, the popped nodes are retained.", name, grown, rounds)
+			}
+			runtime.KeepAlive(deque)
+		}
+	})
+}
+
+func heapInuse() uint64 {
+	runtime.GC()
+	stats := runtime.MemStats{}
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"src/stacks"
+	"src/stacks/dualStack"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// In these test cases we check that a Pop on the empty dual stack waits for a matching Push.
+
+func TestDualStack(t *testing.T) {
+
+	t.Run("Test push and pop order", func(t *testing.T) {
+		stack := dualStack.FreshDualStack[int]()
+		for i := 0; i < 10; i++ {
+			stack.Push(i)
+		}
+		for i := 9; i >= 0; i-- {
+			elem, err := stack.Pop()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			if elem != i {
+				t.Errorf("Received removed element %d != expected removed element %d", elem, i)
+			}
+		}
+	})
+
+	t.Run("Test try pop on empty stack", func(t *testing.T) {
+		stack := dualStack.FreshDualStack[int]()
+		_, err := stack.TryPop()
+		if err == nil || err.Error() != stacks.EmptyStackError {
+			t.Errorf("Error: received an error other than the expected emptyStackError.")
+		}
+		if _, err := stack.Peek(); err == nil || err.Error() != stacks.EmptyStackError {
+			t.Errorf("Error: received an error other than the expected emptyStackError.")
+		}
+	})
+
+	t.Run("Test pop waits for push", func(t *testing.T) {
+		stack := dualStack.FreshDualStack[int]()
+		received := make(chan int)
+		go func() {
+			elem, err := stack.Pop()
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			received <- elem
+		}()
+
+		time.Sleep(5 * time.Millisecond)
+		stack.Push(42)
+		if elem := <-received; elem != 42 {
+			t.Errorf("Received element %d != expected element 42", elem)
+		}
+		if stackLen, _ := stack.Len(); stackLen != 0 {
+			t.Errorf("Received stack size %d != expected stack size 0", stackLen)
+		}
+	})
+
+	t.Run("Test pop timeout", func(t *testing.T) {
+		// The cancelled reservation must not swallow the value pushed afterwards.
+		stack := dualStack.FreshDualStack[int]()
+		_, err := stack.PopTimeout(5 * time.Millisecond)
+		if err == nil || err.Error() != stacks.PopTimeoutError {
+			t.Errorf("Error: received an error other than the expected popTimeoutError.")
+		}
+
+		stack.Push(1)
+		if stackLen, _ := stack.Len(); stackLen != 1 {
+			t.Errorf("Received stack size %d != expected stack size 1", stackLen)
+		}
+		if elem, _ := stack.Peek(); elem != 1 {
+			t.Errorf("Received top %d != expected top 1", elem)
+		}
+	})
+
+	t.Run("Test pop cancellation", func(t *testing.T) {
+		stack := dualStack.FreshDualStack[int]()
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			_, err := stack.PopContext(ctx)
+			done <- err
+		}()
+
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+		if err := <-done; err != context.Canceled {
+			t.Errorf("Received error %v != expected error %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("Test waiting poppers and pushers", func(t *testing.T) {
+		// All poppers come first and wait, then every push must be handed off to exactly one of them.
+		const poppersAmount = 1000
+		stack := dualStack.FreshDualStack[int]()
+		sum := atomic.Int64{}
+
+		wg := sync.WaitGroup{}
+		wg.Add(poppersAmount)
+		for i := 0; i < poppersAmount; i++ {
+			go func() {
+				defer wg.Done()
+				elem, err := stack.Pop()
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+				sum.Add(int64(elem))
+			}()
+		}
+		for i := 1; i <= poppersAmount; i++ {
+			go stack.Push(i)
+		}
+		wg.Wait()
+
+		expected := int64(poppersAmount * (poppersAmount + 1) / 2)
+		if sum.Load() != expected {
+			t.Errorf("Received sum of elements %d != expected sum of elements %d", sum.Load(), expected)
+		}
+		if stackLen, _ := stack.Len(); stackLen != 0 {
+			t.Errorf("Received stack size %d != expected stack size 0", stackLen)
+		}
+	})
+
+	t.Run("Test conservation with timeouts", func(t *testing.T) {
+		// Poppers give up after a short time, every pushed value is either received or left in the stack.
+		const operationsAmount = 10_000
+		stack := dualStack.FreshDualStack[int]()
+		sum := atomic.Int64{}
+
+		wg := sync.WaitGroup{}
+		wg.Add(operationsAmount * 2)
+		for i := 1; i <= operationsAmount; i++ {
+			go func() {
+				defer wg.Done()
+				elem, err := stack.PopTimeout(time.Millisecond)
+				if err == nil {
+					sum.Add(int64(elem))
+				} else if err.Error() != stacks.PopTimeoutError {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				stack.Push(i)
+			}()
+		}
+		wg.Wait()
+
+		for {
+			elem, err := stack.TryPop()
+			if err != nil {
+				break
+			}
+			sum.Add(int64(elem))
+		}
+		expected := int64(operationsAmount * (operationsAmount + 1) / 2)
+		if sum.Load() != expected {
+			t.Errorf("Received sum of elements %d != expected sum of elements %d", sum.Load(), expected)
+		}
+	})
+}
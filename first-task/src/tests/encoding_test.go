@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"src/stacks"
+	"src/stacks/consistentStack"
+	"src/stacks/optimizedTraiberStack"
+	"src/stacks/traiberStack"
+	"testing"
+)
+
+// In these test cases we check that a stack survives encoding and decoding with its order intact.
+
+type encodableStack interface {
+	stacks.Stack[int]
+	json.Marshaler
+	json.Unmarshaler
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	gob.GobEncoder
+	gob.GobDecoder
+}
+
+func TestStacksEncoding(t *testing.T) {
+	for name, newStack := range map[string]func() encodableStack{
+		"Consistent stack":        func() encodableStack { return consistentStack.FreshConsistentStack[int]() },
+		"Traiber stack":           func() encodableStack { return traiberStack.FreshTraiberStack[int]() },
+		"Optimized Traiber stack": func() encodableStack { return optimizedTraiberStack.FreshOptimizedTraiberStack[int]() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			runEncodingTests(t, newStack)
+		})
+	}
+}
+
+func TestConcurrentStackSnapshot(t *testing.T) {
+	// One goroutine pushes 0, 1, 2, ... while the stack is being encoded,
+	// every snapshot must be a contiguous descending run that ends with 0.
+	for name, newStack := range map[string]func() encodableStack{
+		"Traiber stack":           func() encodableStack { return traiberStack.FreshTraiberStack[int]() },
+		"Optimized Traiber stack": func() encodableStack { return optimizedTraiberStack.FreshOptimizedTraiberStack[int]() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			stack := newStack()
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 0; i < tasksAmount; i++ {
+					stack.Push(i)
+				}
+			}()
+
+			for finished := false; !finished; {
+				select {
+				case <-done:
+					finished = true
+				default:
+				}
+				data, err := stack.MarshalJSON()
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err.Error())
+				}
+				var elements []int
+				json.Unmarshal(data, &elements)
+				for i, elem := range elements {
+					if expected := len(elements) - 1 - i; elem != expected {
+						t.Fatalf("Received element %d != expected element %d at position %d", elem, expected, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func runEncodingTests(t *testing.T, newStack func() encodableStack) {
+
+	filled := func() encodableStack {
+		stack := newStack()
+		for i := 0; i < 100; i++ {
+			stack.Push(i)
+		}
+		return stack
+	}
+
+	checkOrder := func(t *testing.T, stack encodableStack, amount int) {
+		t.Helper()
+		for i := amount - 1; i >= 0; i-- {
+			elem, err := stack.Pop()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			if elem != i {
+				t.Fatalf("Received removed element %d != expected removed element %d", elem, i)
+			}
+		}
+		if _, err := stack.Pop(); err == nil {
+			t.Errorf("Error: the decoded stack contains extra elements.")
+		}
+	}
+
+	t.Run("Test JSON round trip", func(t *testing.T) {
+		data, err := json.Marshal(filled())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		decoded := newStack()
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		checkOrder(t, decoded, 100)
+	})
+
+	t.Run("Test JSON lists the top first", func(t *testing.T) {
+		stack := newStack()
+		stack.Push(1)
+		stack.Push(2)
+		data, _ := json.Marshal(stack)
+		if string(data) != "[2,1]" {
+			t.Errorf("Received JSON %s != expected JSON [2,1]", data)
+		}
+	})
+
+	t.Run("Test empty stack", func(t *testing.T) {
+		data, err := json.Marshal(newStack())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if string(data) != "[]" {
+			t.Errorf("Received JSON %s != expected JSON []", data)
+		}
+		binary, err := newStack().MarshalBinary()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		decoded := filled()
+		if err := decoded.UnmarshalBinary(binary); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if stackLen, _ := decoded.Len(); stackLen != 0 {
+			t.Errorf("Received stack size %d != expected stack size 0", stackLen)
+		}
+	})
+
+	t.Run("Test binary round trip", func(t *testing.T) {
+		data, err := filled().MarshalBinary()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		decoded := newStack()
+		decoded.Push(-1) // The previous content is replaced.
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		checkOrder(t, decoded, 100)
+	})
+
+	t.Run("Test gob round trip", func(t *testing.T) {
+		buffer := bytes.Buffer{}
+		if err := gob.NewEncoder(&buffer).Encode(filled()); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		decoded := newStack()
+		if err := gob.NewDecoder(&buffer).Decode(decoded); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		checkOrder(t, decoded, 100)
+	})
+
+	t.Run("Test invalid input", func(t *testing.T) {
+		stack := filled()
+		if err := stack.UnmarshalJSON([]byte(`{"top": 1}`)); err == nil {
+			t.Errorf("Error: invalid JSON was decoded without an error.")
+		}
+		if err := stack.UnmarshalBinary([]byte{1, 2, 3}); err == nil {
+			t.Errorf("Error: invalid binary data was decoded without an error.")
+		}
+		// A failed decoding leaves the stack as it was.
+		checkOrder(t, stack, 100)
+	})
+}
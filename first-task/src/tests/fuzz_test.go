@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"src/stacks"
+	"src/tests/auxiliary"
+	"sync"
+	"testing"
+)
+
+// In these test cases a byte slice is decoded into a sequence of stack operations.
+// Run them with "go test -fuzz FuzzStacksSequential ./tests/" to look for new failing inputs.
+
+var stackFactories = map[string]func() stacks.Stack[int]{
+	sequentialStackName:       auxiliary.FreshConsistentStack,
+	"Traiber stack":           auxiliary.FreshTraiberStack,
+	"Optimized Traiber stack": auxiliary.FreshOptimizedTraiberStack,
+	"Versioned stack":         auxiliary.FreshVersionedStack,
+	"Channel stack":           auxiliary.FreshChannelStack,
+	"Two-lock deque front":    auxiliary.FreshTwoLockDequeFront,
+	"Two-lock deque back":     auxiliary.FreshTwoLockDequeBack,
+	"Anchor deque front":      auxiliary.FreshAnchorDequeFront,
+	"Anchor deque back":       auxiliary.FreshAnchorDequeBack,
+}
+
+const sequentialStackName = "Consistent stack"
+
+const (
+	pushOperation = 0
+	popOperation  = 1
+	peekOperation = 2
+	lenOperation  = 3
+)
+
+func addStackSeeds(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	f.Add([]byte{0, 4, 8, 12, 1, 1, 1, 1, 2, 3})
+	f.Add([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 5, 9, 13, 17, 21, 25, 29, 33, 37, 41, 45})
+}
+
+func FuzzStacksSequential(f *testing.F) {
+	// Every operation is compared with a slice whose end is the top of the stack.
+	addStackSeeds(f)
+	f.Fuzz(func(t *testing.T, operations []byte) {
+		for name, newStack := range stackFactories {
+			stack := newStack()
+			var model []int
+			for i, operation := range operations {
+				// The low bits choose the operation, the high bits are the pushed value.
+				value := int(operation >> 2)
+				switch operation % 4 {
+				case pushOperation:
+					if err := stack.Push(value); err != nil {
+						t.Fatalf("%s, operation %d: unexpected error: %s", name, i, err.Error())
+					}
+					model = append(model, value)
+				case popOperation, peekOperation:
+					var elem int
+					var err error
+					if operation%4 == popOperation {
+						elem, err = stack.Pop()
+					} else {
+						elem, err = stack.Peek()
+					}
+					if len(model) == 0 {
+						if err == nil || err.Error() != stacks.EmptyStackError {
+							t.Fatalf("%s, operation %d: received error %v != expected emptyStackError", name, i, err)
+						}
+						continue
+					}
+					if err != nil {
+						t.Fatalf("%s, operation %d: unexpected error: %s", name, i, err.Error())
+					}
+					if expected := model[len(model)-1]; elem != expected {
+						t.Fatalf("%s, operation %d: received element %d != expected element %d", name, i, elem, expected)
+					}
+					if operation%4 == popOperation {
+						model = model[:len(model)-1]
+					}
+				default:
+					stackLen, err := stack.Len()
+					if err != nil {
+						t.Fatalf("%s, operation %d: unexpected error: %s", name, i, err.Error())
+					}
+					if stackLen != len(model) {
+						t.Fatalf("%s, operation %d: received stack size %d != expected stack size %d", name, i, stackLen, len(model))
+					}
+				}
+			}
+		}
+	})
+}
+
+func FuzzStacksConcurrent(f *testing.F) {
+	// The operations are dealt out to the goroutines like cards. The order is not checked,
+	// but every pushed value must be popped exactly once, either by a goroutine or at the end.
+	addStackSeeds(f)
+	f.Fuzz(func(t *testing.T, operations []byte) {
+		if len(operations) == 0 {
+			return
+		}
+		gorutinesAmount := 2 + int(operations[0]%7)
+		operations = operations[1:]
+
+		for name, newStack := range stackFactories {
+			if name == sequentialStackName {
+				continue // Not meant for concurrent use.
+			}
+			stack := newStack()
+			popped := make([][]int, gorutinesAmount)
+			failed := make([]error, gorutinesAmount)
+
+			wg := sync.WaitGroup{}
+			wg.Add(gorutinesAmount)
+			for g := 0; g < gorutinesAmount; g++ {
+				go func() {
+					defer wg.Done()
+					for i := g; i < len(operations); i += gorutinesAmount {
+						var err error
+						switch operations[i] % 4 {
+						case pushOperation:
+							// The index of the operation is a unique value.
+							err = stack.Push(i)
+						case popOperation:
+							var elem int
+							if elem, err = stack.Pop(); err == nil {
+								popped[g] = append(popped[g], elem)
+							}
+						case peekOperation:
+							_, err = stack.Peek()
+						default:
+							_, err = stack.Len()
+						}
+						if err != nil && err.Error() != stacks.EmptyStackError {
+							failed[g] = err
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			for _, err := range failed {
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %s", name, err.Error())
+				}
+			}
+			seen := make([]int, len(operations))
+			for _, values := range popped {
+				for _, value := range values {
+					seen[value]++
+				}
+			}
+			for {
+				elem, err := stack.Pop()
+				if err != nil {
+					break
+				}
+				seen[elem]++
+			}
+			for i, operation := range operations {
+				pushed := 0
+				if operation%4 == pushOperation {
+					pushed = 1
+				}
+				if seen[i] != pushed {
+					t.Fatalf("%s: value %d was popped %d times, pushed %d times", name, i, seen[i], pushed)
+				}
+			}
+		}
+	})
+}
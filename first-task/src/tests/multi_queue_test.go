@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"math/rand"
+	"runtime"
+	"src/queues"
+	"src/queues/multiQueue"
+	"sync"
+	"testing"
+)
+
+// In these test cases we check that the MultiQueue keeps all the elements
+// and measure how far the removed elements are from the real minimum.
+
+const queueElementsAmount = 20_000
+
+func TestMultiQueue(t *testing.T) {
+
+	t.Run("Test empty queue", func(t *testing.T) {
+		queue := multiQueue.FreshMultiQueue[string, int](2)
+		_, _, err := queue.DeleteMin()
+		if err == nil {
+			t.Errorf("Error: some value was received instead of the expected emptyQueueError.")
+		} else if err.Error() != queues.EmptyQueueError {
+			t.Errorf("Error: received an error other than the expected emptyQueueError.")
+		}
+	})
+
+	t.Run("Test single heap is exact", func(t *testing.T) {
+		// With one heap the relaxation disappears and the queue is a usual priority queue.
+		previous := runtime.GOMAXPROCS(1)
+		queue := multiQueue.FreshMultiQueue[int, int](1)
+		runtime.GOMAXPROCS(previous)
+
+		for _, key := range rand.Perm(1000) {
+			queue.Insert(key, -key)
+		}
+		for expected := 0; expected < 1000; expected++ {
+			key, value, err := queue.DeleteMin()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			if key != expected || value != -expected {
+				t.Fatalf("Received (%d, %d) != expected (%d, %d)", key, value, expected, -expected)
+			}
+		}
+	})
+
+	t.Run("Test rank error", func(t *testing.T) {
+		// The rank of a removed key is the number of smaller keys still in the queue.
+		// For two random choices it is proportional to the number of heaps on average.
+		queue := multiQueue.FreshMultiQueue[int, int](2)
+		present := freshFenwickTree(queueElementsAmount)
+		for _, key := range rand.Perm(queueElementsAmount) {
+			queue.Insert(key, key)
+			present.add(key, 1)
+		}
+
+		total := 0
+		for i := 0; i < queueElementsAmount; i++ {
+			key, _, err := queue.DeleteMin()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			total += present.prefixSum(key)
+			present.add(key, -1)
+		}
+
+		mean := float64(total) / queueElementsAmount
+		bound := float64(2 * queue.HeapsAmount())
+		t.Logf("Mean rank error %.2f with %d heaps", mean, queue.HeapsAmount())
+		if mean > bound {
+			t.Errorf("Received mean rank error %.2f > expected bound %.2f", mean, bound)
+		}
+	})
+
+	t.Run("Test parallel insert and delete", func(t *testing.T) {
+		// Every inserted key must be removed exactly once.
+		const gorutinesAmount = 8
+		queue := multiQueue.FreshMultiQueue[int, int](2)
+		removed := make([][]int, gorutinesAmount)
+
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for i := 0; i < gorutinesAmount; i++ {
+			go func() {
+				defer wg.Done()
+				for j := 0; j < queueElementsAmount/gorutinesAmount; j++ {
+					queue.Insert(i*queueElementsAmount/gorutinesAmount+j, 0)
+					if key, _, err := queue.DeleteMin(); err == nil {
+						removed[i] = append(removed[i], key)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		seen := make([]int, queueElementsAmount)
+		for _, keys := range removed {
+			for _, key := range keys {
+				seen[key]++
+			}
+		}
+		for {
+			key, _, err := queue.DeleteMin()
+			if err != nil {
+				break
+			}
+			seen[key]++
+		}
+		for key, times := range seen {
+			if times != 1 {
+				t.Fatalf("Key %d was removed %d times", key, times)
+			}
+		}
+		if queueLen, _ := queue.Len(); queueLen != 0 {
+			t.Errorf("Received queue size %d != expected queue size 0", queueLen)
+		}
+	})
+}
+
+// Fenwick tree over the keys, counts how many of them are still in the queue.
+type fenwickTree []int
+
+func freshFenwickTree(size int) fenwickTree {
+	return make(fenwickTree, size+1)
+}
+
+func (tree fenwickTree) add(key, delta int) {
+	for i := key + 1; i < len(tree); i += i & -i {
+		tree[i] += delta
+	}
+}
+
+func (tree fenwickTree) prefixSum(key int) int {
+	// The amount of keys strictly less than key.
+	sum := 0
+	for i := key; i > 0; i -= i & -i {
+		sum += tree[i]
+	}
+	return sum
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime/trace"
+	"src/stacks"
+	"src/stacks/observers"
+	"src/stacks/optimizedTraiberStack"
+	"src/stacks/traiberStack"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// In these test cases we check that the stacks report every operation and event to the observer.
+
+type observedStack interface {
+	stacks.Stack[int]
+	SetObserver(stacks.Observer)
+}
+
+type countingObserver struct {
+	begun  [2]atomic.Int64
+	ended  [2]atomic.Int64
+	events [2][4]atomic.Int64
+}
+
+func (observer *countingObserver) Begin(operation stacks.Operation) func() {
+	observer.begun[operation].Add(1)
+	return func() { observer.ended[operation].Add(1) }
+}
+
+func (observer *countingObserver) Event(operation stacks.Operation, event stacks.Event) {
+	observer.events[operation][event].Add(1)
+}
+
+func TestObservers(t *testing.T) {
+	for name, newStack := range map[string]func() observedStack{
+		"Traiber stack":           func() observedStack { return traiberStack.FreshTraiberStack[int]() },
+		"Optimized Traiber stack": func() observedStack { return optimizedTraiberStack.FreshOptimizedTraiberStack[int]() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			runObserverTests(t, newStack)
+		})
+	}
+}
+
+func runObserverTests(t *testing.T, newStack func() observedStack) {
+
+	t.Run("Test every operation is observed", func(t *testing.T) {
+		const gorutinesAmount = 8
+		const perGorutine = tasksAmount / gorutinesAmount
+		stack := newStack()
+		observer := &countingObserver{}
+		stack.SetObserver(observer)
+
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for i := 0; i < gorutinesAmount; i++ {
+			go func() {
+				defer wg.Done()
+				for j := 0; j < perGorutine; j++ {
+					stack.Push(j)
+					stack.Pop()
+				}
+			}()
+		}
+		wg.Wait()
+
+		for _, operation := range []stacks.Operation{stacks.PushOperation, stacks.PopOperation} {
+			if begun := observer.begun[operation].Load(); begun != gorutinesAmount*perGorutine {
+				t.Errorf("Received %s begins %d != expected %s begins %d", operation, begun, operation, gorutinesAmount*perGorutine)
+			}
+			if begun, ended := observer.begun[operation].Load(), observer.ended[operation].Load(); begun != ended {
+				t.Errorf("Received %s ends %d != expected %s ends %d", operation, ended, operation, begun)
+			}
+			// Every elimination attempt ends either with an exchange or with a timeout.
+			events := &observer.events[operation]
+			attempts := events[stacks.EliminationAttempt].Load()
+			outcomes := events[stacks.ExchangeSuccess].Load() + events[stacks.ExchangeTimeout].Load()
+			if attempts != outcomes {
+				t.Errorf("Received %s exchange outcomes %d != expected elimination attempts %d", operation, outcomes, attempts)
+			}
+		}
+	})
+
+	t.Run("Test slog observer", func(t *testing.T) {
+		output := bytes.Buffer{}
+		logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		stack := newStack()
+		stack.SetObserver(observers.FreshSlogObserver(logger))
+		stack.Push(1)
+		stack.Pop()
+		for _, expected := range []string{"operation=push", "operation=pop"} {
+			if !strings.Contains(output.String(), expected) {
+				t.Errorf("Error: the log does not contain %q.", expected)
+			}
+		}
+	})
+
+	t.Run("Test trace observer", func(t *testing.T) {
+		if trace.IsEnabled() {
+			t.Skip("Tracing is already enabled.")
+		}
+		output := bytes.Buffer{}
+		if err := trace.Start(&output); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		observer := observers.FreshTraceObserver(context.Background(), "stack test")
+		stack := newStack()
+		stack.SetObserver(observer)
+		stack.Push(1)
+		stack.Pop()
+		observer.End()
+		trace.Stop()
+		if output.Len() == 0 {
+			t.Errorf("Error: the trace is empty.")
+		}
+	})
+}
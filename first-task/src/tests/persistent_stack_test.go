@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"src/stacks"
+	"src/stacks/persistentStack"
+	"src/stacks/stacktest"
+	"src/tests/auxiliary"
+	"sync"
+	"testing"
+)
+
+// In these test cases we check that old versions of the persistent stack never change
+// and that the versioned wrapper can tag and restore them.
+
+func TestVersionedStack(t *testing.T) {
+	stacktest.Run(t, stacktest.Ints(auxiliary.FreshVersionedStack))
+}
+
+func TestPersistentStack(t *testing.T) {
+
+	t.Run("Test versions are independent", func(t *testing.T) {
+		empty := persistentStack.FreshPersistentStack[int]()
+		one := empty.Push(1)
+		two := one.Push(2)
+		other := one.Push(3)
+		popped, elem, err := two.Pop()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if elem != 2 || !popped.Same(one) {
+			t.Errorf("Error: popping the pushed element did not return the previous version.")
+		}
+
+		for _, expected := range []struct {
+			version  persistentStack.Stack[int]
+			elements []int
+		}{{empty, nil}, {one, []int{1}}, {two, []int{2, 1}}, {other, []int{3, 1}}} {
+			if expected.version.Len() != len(expected.elements) {
+				t.Errorf("Received stack len %d != expected stack len %d", expected.version.Len(), len(expected.elements))
+			}
+			current := expected.version
+			for _, want := range expected.elements {
+				var got int
+				current, got, err = current.Pop()
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err.Error())
+				}
+				if got != want {
+					t.Errorf("Received removed element %d != expected removed element %d", got, want)
+				}
+			}
+			if _, _, err := current.Pop(); err == nil || err.Error() != stacks.EmptyStackError {
+				t.Errorf("Error: received an error other than the expected emptyStackError.")
+			}
+		}
+	})
+
+	t.Run("Test tag and restore", func(t *testing.T) {
+		stack := persistentStack.FreshVersionedStack[int]()
+		stack.Push(1)
+		stack.Push(2)
+		tagged, _ := stack.Tag("before")
+		stack.Pop()
+		stack.Push(3)
+		stack.Push(4)
+
+		if err := stack.Restore("before"); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if current, _ := stack.Current(); !current.Same(tagged) {
+			t.Errorf("Error: the restored version differs from the tagged one.")
+		}
+		if elem, _ := stack.Peek(); elem != 2 {
+			t.Errorf("Received top %d != expected top 2", elem)
+		}
+		if stackLen, _ := stack.Len(); stackLen != 2 {
+			t.Errorf("Received stack len %d != expected stack len 2", stackLen)
+		}
+	})
+
+	t.Run("Test unknown tag", func(t *testing.T) {
+		stack := persistentStack.FreshVersionedStack[int]()
+		stack.Tag("tag")
+		stack.Untag("tag")
+		for _, err := range []error{stack.Restore("tag"), stack.Restore("never")} {
+			if err == nil || err.Error() != stacks.UnknownVersionError {
+				t.Errorf("Error: received an error other than the expected unknownVersionError.")
+			}
+		}
+	})
+
+	t.Run("Test tagged snapshots under concurrent pushes", func(t *testing.T) {
+		// One goroutine pushes 0, 1, 2, ..., the other one tags the stack all the time:
+		// every tagged version must hold a contiguous descending run that ends with 0.
+		stack := persistentStack.FreshVersionedStack[int]()
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < tasksAmount; i++ {
+				stack.Push(i)
+			}
+		}()
+
+		for i := 0; i < 1000; i++ {
+			version, _ := stack.Tag("snapshot")
+			for expected := version.Len() - 1; expected >= 0; expected-- {
+				var elem int
+				version, elem, _ = version.Pop()
+				if elem != expected {
+					t.Fatalf("Received removed element %d != expected removed element %d", elem, expected)
+				}
+			}
+		}
+		wg.Wait()
+	})
+
+	t.Run("Test nil versioned stack", func(t *testing.T) {
+		var stack *persistentStack.Versioned[int]
+		_, currentErr := stack.Current()
+		_, publishErr := stack.Publish(persistentStack.Stack[int]{})
+		_, tagErr := stack.Tag("tag")
+		for _, err := range []error{currentErr, publishErr, tagErr, stack.Restore("tag")} {
+			if err == nil || err.Error() != stacks.StackNilPointerError {
+				t.Errorf("Error: received an error other than the expected nilPointerError.")
+			}
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"context"
+	"src/pools"
+	"src/pools/workStealingPool"
+	"src/tests/auxiliary"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// In these test cases we check that the goroutine pools run every submitted task exactly once.
+
+func TestWorkStealingPool(t *testing.T) {
+	runPoolTests(t, auxiliary.FreshWorkStealingPool)
+}
+
+func TestWorkStealingPoolLocality(t *testing.T) {
+	// Every worker is kept busy by one parent task, so nobody is free to steal a child in the meantime.
+	// A worker that finished its parent finds its own child first, and the child holds the worker
+	// until all children have started, hence every child has to run on the worker of its parent.
+	const workersAmount = 4
+	pool := workStealingPool.FreshWorkStealingPool(workersAmount)
+	defer pool.Shutdown(context.Background())
+
+	parents, children := sync.WaitGroup{}, sync.WaitGroup{}
+	parents.Add(workersAmount)
+	children.Add(workersAmount)
+	pushed := sync.WaitGroup{}
+	pushed.Add(workersAmount)
+	misplaced := atomic.Int64{}
+	for i := 0; i < workersAmount; i++ {
+		pool.Spawn(func(parent *workStealingPool.Worker) {
+			parents.Done()
+			parents.Wait()
+			err := parent.Submit(func(child *workStealingPool.Worker) {
+				if child != parent {
+					misplaced.Add(1)
+				}
+				children.Done()
+				children.Wait()
+			})
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			pushed.Done()
+			pushed.Wait()
+		})
+	}
+	pool.Wait()
+
+	if misplaced.Load() != 0 {
+		t.Errorf("Error: %d of %d children ran on a worker other than their parent's one.", misplaced.Load(), workersAmount)
+	}
+}
+
+func TestChannelPool(t *testing.T) {
+	runPoolTests(t, auxiliary.FreshChannelPool)
+}
+
+const tasksAmount = 100_000
+
+func runPoolTests(t *testing.T, newPool func() pools.Pool) {
+
+	t.Run("Test submit and wait", func(t *testing.T) {
+		pool := newPool()
+		defer pool.Shutdown(context.Background())
+
+		counter := atomic.Int64{}
+		for i := 0; i < tasksAmount; i++ {
+			err := pool.Submit(func() {
+				counter.Add(1)
+			})
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+		}
+		pool.Wait()
+
+		if counter.Load() != tasksAmount {
+			t.Errorf("Received executed tasks %d != expected executed tasks %d", counter.Load(), tasksAmount)
+		}
+	})
+
+	t.Run("Test fork-join", func(t *testing.T) {
+		// Every task submits its children itself, Wait must not return before the whole tree is computed.
+		pool := newPool()
+		defer pool.Shutdown(context.Background())
+
+		const depth = 14
+		counter := atomic.Int64{}
+		var spawn func(level int)
+		spawn = func(level int) {
+			counter.Add(1)
+			if level == depth {
+				return
+			}
+			for i := 0; i < 2; i++ {
+				if err := pool.Submit(func() { spawn(level + 1) }); err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+			}
+		}
+		pool.Submit(func() { spawn(0) })
+		pool.Wait()
+
+		expected := int64(1)<<(depth+1) - 1
+		if counter.Load() != expected {
+			t.Errorf("Received executed tasks %d != expected executed tasks %d", counter.Load(), expected)
+		}
+	})
+
+	t.Run("Test shutdown drains queued tasks", func(t *testing.T) {
+		pool := newPool()
+		counter := atomic.Int64{}
+		for i := 0; i < tasksAmount; i++ {
+			pool.Submit(func() {
+				counter.Add(1)
+			})
+		}
+
+		if err := pool.Shutdown(context.Background()); err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+		if counter.Load() != tasksAmount {
+			t.Errorf("Received executed tasks %d != expected executed tasks %d", counter.Load(), tasksAmount)
+		}
+	})
+
+	t.Run("Test submit after shutdown", func(t *testing.T) {
+		pool := newPool()
+		pool.Shutdown(context.Background())
+
+		err := pool.Submit(func() {})
+		if err == nil {
+			t.Errorf("Error: the task was accepted instead of the expected poolClosedError.")
+		} else if err.Error() != pools.PoolClosedError {
+			t.Errorf("Error: received an error other than the expected poolClosedError.")
+		}
+	})
+
+	t.Run("Test shutdown deadline", func(t *testing.T) {
+		// A task that never finishes in time must not block Shutdown beyond the context deadline.
+		pool := newPool()
+		release := make(chan struct{})
+		pool.Submit(func() {
+			<-release
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := pool.Shutdown(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Received error %v != expected error %v", err, context.DeadlineExceeded)
+		}
+
+		close(release)
+		if err := pool.Shutdown(context.Background()); err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"context"
+	"src/queues"
+	"src/queues/ringBuffer"
+	"src/tests/auxiliary"
+	"sync"
+	"testing"
+	"time"
+)
+
+// In these test cases we check the bounded ring buffer both sequentially and in parallel.
+
+func TestRingBuffer(t *testing.T) {
+
+	t.Run("Test capacity is a power of two", func(t *testing.T) {
+		for capacity, expected := range map[int]int{0: 2, 1: 2, 2: 2, 3: 4, 1000: 1024, 1024: 1024} {
+			buffer := ringBuffer.FreshRingBuffer[int](capacity)
+			if buffer.Cap() != expected {
+				t.Errorf("Received capacity %d != expected capacity %d", buffer.Cap(), expected)
+			}
+		}
+	})
+
+	t.Run("Test empty queue dequeue", func(t *testing.T) {
+		buffer := auxiliary.FreshRingBuffer()
+		elem, err := buffer.TryDequeue()
+		if err == nil {
+			t.Errorf("Error: value %d was received instead of the expected emptyQueueError.", elem)
+		} else if err.Error() != queues.EmptyQueueError {
+			t.Errorf("Error: received an error other than the expected emptyQueueError.")
+		}
+	})
+
+	t.Run("Test full queue enqueue", func(t *testing.T) {
+		buffer := ringBuffer.FreshRingBuffer[int](8)
+		for i := 0; i < 8; i++ {
+			if err := buffer.TryEnqueue(i); err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+		}
+		err := buffer.TryEnqueue(8)
+		if err == nil {
+			t.Errorf("Error: the value was accepted instead of the expected fullQueueError.")
+		} else if err.Error() != queues.FullQueueError {
+			t.Errorf("Error: received an error other than the expected fullQueueError.")
+		}
+	})
+
+	t.Run("Test FIFO order over several laps", func(t *testing.T) {
+		buffer := ringBuffer.FreshRingBuffer[int](4)
+		next := 0
+		for i := 0; i < 100; i++ {
+			buffer.TryEnqueue(2 * i)
+			buffer.TryEnqueue(2*i + 1)
+			for j := 0; j < 2; j++ {
+				elem, err := buffer.TryDequeue()
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err.Error())
+				}
+				if elem != next {
+					t.Fatalf("Received element %d != expected element %d", elem, next)
+				}
+				next++
+			}
+		}
+		if length, _ := buffer.Len(); length != 0 {
+			t.Errorf("Received queue len %d != expected queue len 0", length)
+		}
+	})
+
+	t.Run("Test blocking dequeue waits for enqueue", func(t *testing.T) {
+		buffer := auxiliary.FreshRingBuffer()
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			buffer.TryEnqueue(42)
+		}()
+		elem, err := buffer.Dequeue(context.Background())
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+		if elem != 42 {
+			t.Errorf("Received element %d != expected element 42", elem)
+		}
+	})
+
+	t.Run("Test blocking operations respect the context", func(t *testing.T) {
+		buffer := ringBuffer.FreshRingBuffer[int](2)
+		buffer.TryEnqueue(1)
+		buffer.TryEnqueue(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		if err := buffer.Enqueue(ctx, 2); err != context.DeadlineExceeded {
+			t.Errorf("Received error %v != expected error %v", err, context.DeadlineExceeded)
+		}
+
+		buffer.TryDequeue()
+		buffer.TryDequeue()
+		if _, err := buffer.Dequeue(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Received error %v != expected error %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("Test parallel producers and consumers", func(t *testing.T) {
+		// Every producer enqueues its own range of values, and every value must be received exactly once,
+		// although the buffer is much smaller than the flow.
+		const producersAmount = 8
+		const perProducer = tasksAmount / producersAmount
+		buffer := ringBuffer.FreshRingBuffer[int](64)
+		received := make([][]int, producersAmount)
+		ctx := context.Background()
+
+		wg := sync.WaitGroup{}
+		wg.Add(producersAmount * 2)
+		for i := 0; i < producersAmount; i++ {
+			go func() {
+				defer wg.Done()
+				for j := 0; j < perProducer; j++ {
+					if err := buffer.Enqueue(ctx, i*perProducer+j); err != nil {
+						t.Errorf("Unexpected error: %s", err.Error())
+					}
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < perProducer; j++ {
+					elem, err := buffer.Dequeue(ctx)
+					if err != nil {
+						t.Errorf("Unexpected error: %s", err.Error())
+						return
+					}
+					received[i] = append(received[i], elem)
+				}
+			}()
+		}
+		wg.Wait()
+
+		seen := make([]int, producersAmount*perProducer)
+		for _, values := range received {
+			for _, value := range values {
+				seen[value]++
+			}
+		}
+		for value, count := range seen {
+			if count != 1 {
+				t.Fatalf("Error: value %d was received %d times instead of exactly once.", value, count)
+			}
+		}
+		if _, err := buffer.TryDequeue(); err == nil {
+			t.Errorf("Error: the buffer is not empty after all values were received.")
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package tests
+
+import (
+	"math/rand"
+	"src/stacks"
+	"src/stacks/traiberStack"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// In these test cases we check savepoints of the Traiber stack. Besides the usual scenarios,
+// small random histories of pushes, pops, marks and rewinds are run concurrently and replayed
+// against a sequential stack with savepoints in every order the timestamps allow.
+
+func TestSavepoints(t *testing.T) {
+
+	t.Run("Test rewind discards pushed elements", func(t *testing.T) {
+		stack := traiberStack.FreshTraiberStack[int]()
+		stack.Push(1)
+		mark := stack.Mark()
+		stack.Push(2)
+		stack.Push(3)
+
+		discarded, err := stack.RewindTo(mark)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if discarded != 2 {
+			t.Errorf("Received discarded elements %d != expected discarded elements 2", discarded)
+		}
+		if elem, _ := stack.Peek(); elem != 1 {
+			t.Errorf("Received top %d != expected top 1", elem)
+		}
+		// The mark stays valid, rewinding again discards nothing.
+		if discarded, err := stack.RewindTo(mark); err != nil || discarded != 0 {
+			t.Errorf("Received discarded elements %d and error %v != expected 0 and no error", discarded, err)
+		}
+	})
+
+	t.Run("Test rewind to empty stack", func(t *testing.T) {
+		stack := traiberStack.FreshTraiberStack[int]()
+		mark := stack.Mark()
+		for i := 0; i < 10; i++ {
+			stack.Push(i)
+		}
+		if discarded, err := stack.RewindTo(mark); err != nil || discarded != 10 {
+			t.Errorf("Received discarded elements %d and error %v != expected 10 and no error", discarded, err)
+		}
+		if stackLen, _ := stack.Len(); stackLen != 0 {
+			t.Errorf("Received stack size %d != expected stack size 0", stackLen)
+		}
+	})
+
+	t.Run("Test marked element popped", func(t *testing.T) {
+		stack := traiberStack.FreshTraiberStack[int]()
+		stack.Push(1)
+		stack.Push(2)
+		mark := stack.Mark()
+		stack.Pop()
+		stack.Push(3) // The top has a new element, but the marked one is gone.
+
+		_, err := stack.RewindTo(mark)
+		if err == nil || err.Error() != stacks.MarkInvalidatedError {
+			t.Errorf("Error: received an error other than the expected markInvalidatedError.")
+		}
+		if stackLen, _ := stack.Len(); stackLen != 2 {
+			t.Errorf("Received stack size %d != expected stack size 2", stackLen)
+		}
+	})
+
+	t.Run("Test foreign mark", func(t *testing.T) {
+		stack := traiberStack.FreshTraiberStack[int]()
+		other := traiberStack.FreshTraiberStack[int]()
+		var nilStack *traiberStack.Stack[int]
+		for _, mark := range []traiberStack.Mark[int]{other.Mark(), nilStack.Mark(), {}} {
+			_, err := stack.RewindTo(mark)
+			if err == nil || err.Error() != stacks.MarkInvalidatedError {
+				t.Errorf("Error: received an error other than the expected markInvalidatedError.")
+			}
+		}
+		if _, err := nilStack.RewindTo(stack.Mark()); err == nil || err.Error() != stacks.StackNilPointerError {
+			t.Errorf("Error: received an error other than the expected nilPointerError.")
+		}
+	})
+
+	t.Run("Test linearizability", func(t *testing.T) {
+		const historiesAmount = 2000
+		for i := 0; i < historiesAmount; i++ {
+			history := recordSavepointHistory(rand.New(rand.NewSource(int64(i))))
+			if !linearizable(history, freshSavepointModel()) {
+				t.Fatalf("History %d is not linearizable: %v", i, history)
+			}
+		}
+	})
+}
+
+type savepointOperation struct {
+	kind    int // One of pushOperation, popOperation, markOperation and rewindOperation.
+	slot    int // The mark used by the operation: gorutine * savepointOperationsAmount + index.
+	value   int // The pushed, popped or discarded amount.
+	failed  bool
+	call    int64
+	respond int64
+}
+
+const (
+	markOperation   = 4
+	rewindOperation = 5
+)
+
+const savepointGorutinesAmount = 3
+const savepointOperationsAmount = 4
+
+func recordSavepointHistory(random *rand.Rand) []savepointOperation {
+	// A goroutine rewinds only to its own latest mark, and a rewind planned before any mark becomes a pop,
+	// so the mark of every recorded rewind has responded before the rewind was called.
+	stack := traiberStack.FreshTraiberStack[int]()
+	clock := atomic.Int64{}
+	histories := make([][]savepointOperation, savepointGorutinesAmount)
+	plans := make([][]int, savepointGorutinesAmount)
+	for g := range plans {
+		for i := 0; i < savepointOperationsAmount; i++ {
+			plans[g] = append(plans[g], []int{pushOperation, popOperation, markOperation, rewindOperation}[random.Intn(4)])
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(savepointGorutinesAmount)
+	for g := 0; g < savepointGorutinesAmount; g++ {
+		go func() {
+			defer wg.Done()
+			marks := map[int]traiberStack.Mark[int]{}
+			lastMark := -1
+			for i, kind := range plans[g] {
+				operation := savepointOperation{kind: kind, slot: g*savepointOperationsAmount + i}
+				if kind == rewindOperation {
+					if lastMark < 0 {
+						kind, operation.kind = popOperation, popOperation
+					} else {
+						operation.slot = lastMark
+					}
+				}
+				operation.call = clock.Add(1)
+				switch kind {
+				case pushOperation:
+					operation.value = operation.slot + 1 // Pushed values are unique.
+					stack.Push(operation.value)
+				case popOperation:
+					elem, err := stack.Pop()
+					operation.value, operation.failed = elem, err != nil
+				case markOperation:
+					marks[operation.slot] = stack.Mark()
+					lastMark = operation.slot
+				default:
+					discarded, err := stack.RewindTo(marks[operation.slot])
+					operation.value, operation.failed = discarded, err != nil
+				}
+				operation.respond = clock.Add(1)
+				histories[g] = append(histories[g], operation)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var history []savepointOperation
+	for _, operations := range histories {
+		history = append(history, operations...)
+	}
+	return history
+}
+
+type savepointModel struct {
+	elements []int       // The bottom is first.
+	marks    map[int]int // The marked top value, 0 for the empty stack.
+}
+
+func freshSavepointModel() savepointModel {
+	return savepointModel{marks: map[int]int{}}
+}
+
+func (model savepointModel) apply(operation savepointOperation) (savepointModel, bool) {
+	// Returns the next state and whether the recorded result agrees with the sequential stack.
+	// Pushed values are unique, so a mark keeps the value of the marked top and finds the marked element
+	// by it. A mark whose element is gone must fail the rewind and leave the stack alone, while the mark
+	// of the empty stack lies below the bottom and never fails.
+	next := savepointModel{elements: append([]int(nil), model.elements...), marks: model.marks}
+	top := len(next.elements) - 1
+	switch operation.kind {
+	case pushOperation:
+		next.elements = append(next.elements, operation.value)
+		return next, true
+	case popOperation:
+		if top < 0 {
+			return next, operation.failed
+		}
+		next.elements = next.elements[:top]
+		return next, !operation.failed && operation.value == model.elements[top]
+	case markOperation:
+		next.marks = map[int]int{}
+		for slot, value := range model.marks {
+			next.marks[slot] = value
+		}
+		next.marks[operation.slot] = 0
+		if top >= 0 {
+			next.marks[operation.slot] = model.elements[top]
+		}
+		return next, true
+	default:
+		marked := model.marks[operation.slot]
+		position := -1 // The marked element is at position, the empty mark is below the bottom.
+		if marked != 0 {
+			position = len(model.elements)
+			for i, elem := range model.elements {
+				if elem == marked {
+					position = i
+				}
+			}
+			if position == len(model.elements) {
+				return next, operation.failed
+			}
+		}
+		next.elements = next.elements[:position+1]
+		return next, !operation.failed && operation.value == top-position
+	}
+}
+
+func linearizable(history []savepointOperation, model savepointModel) bool {
+	// Backtracking over the orders: an operation may go next when it was called before the earliest
+	// response among the remaining ones. A rewind has to wait until its mark has been placed.
+	if len(history) == 0 {
+		return true
+	}
+	earliestRespond := history[0].respond
+	for _, operation := range history {
+		earliestRespond = min(earliestRespond, operation.respond)
+	}
+	for i, operation := range history {
+		if operation.call > earliestRespond {
+			continue
+		}
+		if operation.kind == rewindOperation {
+			if _, marked := model.marks[operation.slot]; !marked {
+				continue // Its mark is still among the remaining operations.
+			}
+		}
+		next, ok := model.apply(operation)
+		if !ok {
+			continue
+		}
+		rest := append(append([]savepointOperation(nil), history[:i]...), history[i+1:]...)
+		if linearizable(rest, next) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"context"
+	"runtime"
+	"src/stacks"
+	"src/stacks/stackChannels"
+	"src/stacks/stacktest"
+	"src/stacks/traiberStack"
+	"src/tests/auxiliary"
+	"testing"
+	"time"
+)
+
+// In these test cases we check the channel adapters of the stacks and that they leave no goroutines behind.
+
+func TestChannelStack(t *testing.T) {
+	factory := stacktest.Ints(auxiliary.FreshChannelStack)
+	factory.ElementsAmount = tasksAmount
+	stacktest.Run(t, factory)
+
+	t.Run("Test elements from the channel", func(t *testing.T) {
+		source := make(chan int, 10)
+		stack := stackChannels.FreshChannelStack(source)
+		source <- 1
+		source <- 2
+		stack.Push(3)
+		source <- 4
+		for _, expected := range []int{4, 3, 2, 1} {
+			elem, err := stack.Pop()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			if elem != expected {
+				t.Errorf("Received removed element %d != expected removed element %d", elem, expected)
+			}
+		}
+	})
+
+	t.Run("Test pop waits for the channel", func(t *testing.T) {
+		source := make(chan int)
+		stack := stackChannels.FreshChannelStack(source)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			source <- 7
+			close(source)
+		}()
+
+		elem, err := stack.PopContext(context.Background())
+		if err != nil || elem != 7 {
+			t.Errorf("Received element %d and error %v != expected element 7 and no error", elem, err)
+		}
+		// The channel is closed and the stack is empty, so there is nothing to wait for.
+		if _, err := stack.PopContext(context.Background()); err == nil || err.Error() != stacks.EmptyStackError {
+			t.Errorf("Error: received an error other than the expected emptyStackError.")
+		}
+	})
+
+	t.Run("Test pop wait is cancelled", func(t *testing.T) {
+		stack := stackChannels.FreshChannelStack(make(chan int))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := stack.PopContext(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Received error %v != expected error %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("Test pop without a channel", func(t *testing.T) {
+		// Nothing can ever arrive, so an empty stack fails at once instead of waiting for the context.
+		stack := stackChannels.FreshChannelStack[int](nil)
+		_, err := stack.PopContext(context.Background())
+		if err == nil || err.Error() != stacks.EmptyStackError {
+			t.Errorf("Error: received an error other than the expected emptyStackError.")
+		}
+	})
+}
+
+func TestStackAdapter(t *testing.T) {
+
+	t.Run("Test LIFO order", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		adapter := stackChannels.FreshAdapter[int](context.Background(), traiberStack.FreshTraiberStack[int](), 0)
+		for i := 0; i < 10; i++ {
+			adapter.In() <- i
+		}
+		close(adapter.In())
+
+		expected := 9
+		for elem := range adapter.Out() {
+			if elem != expected {
+				t.Errorf("Received element %d != expected element %d", elem, expected)
+			}
+			expected--
+		}
+		if expected != -1 {
+			t.Errorf("Received %d elements != expected 10 elements", 9-expected)
+		}
+		if err := adapter.Err(); err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+		checkNoGoroutineLeak(t, before)
+	})
+
+	t.Run("Test elements already in the stack", func(t *testing.T) {
+		stack := traiberStack.FreshTraiberStack[int]()
+		stack.Push(1)
+		stack.Push(2)
+		adapter := stackChannels.FreshAdapter[int](context.Background(), stack, 0)
+		close(adapter.In())
+		if first, second := <-adapter.Out(), <-adapter.Out(); first != 2 || second != 1 {
+			t.Errorf("Received elements %d, %d != expected elements 2, 1", first, second)
+		}
+	})
+
+	t.Run("Test back-pressure", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		ctx, cancel := context.WithCancel(context.Background())
+		adapter := stackChannels.FreshAdapter[int](ctx, traiberStack.FreshTraiberStack[int](), 3)
+		for i := 0; i < 3; i++ {
+			adapter.In() <- i
+		}
+		select {
+		case adapter.In() <- 3:
+			t.Errorf("Error: the adapter accepted an element above its capacity.")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		if elem := <-adapter.Out(); elem != 2 {
+			t.Errorf("Received element %d != expected element 2", elem)
+		}
+		select {
+		case adapter.In() <- 3:
+		case <-time.After(time.Second):
+			t.Errorf("Error: the adapter did not accept an element after one was received.")
+		}
+		cancel()
+		<-adapter.Done()
+		checkNoGoroutineLeak(t, before)
+	})
+
+	t.Run("Test cancellation", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		stack := traiberStack.FreshTraiberStack[int]()
+		ctx, cancel := context.WithCancel(context.Background())
+		adapter := stackChannels.FreshAdapter[int](ctx, stack, 0)
+		for i := 0; i < 5; i++ {
+			adapter.In() <- i
+		}
+		cancel()
+
+		if err := adapter.Err(); err != context.Canceled {
+			t.Errorf("Received error %v != expected error %v", err, context.Canceled)
+		}
+		if _, ok := <-adapter.Out(); ok {
+			t.Errorf("Error: the output channel is still open.")
+		}
+		// The undelivered elements are kept in the stack in their order.
+		if elem, _ := stack.Peek(); elem != 4 {
+			t.Errorf("Received top %d != expected top 4", elem)
+		}
+		if stackLen, _ := stack.Len(); stackLen != 5 {
+			t.Errorf("Received stack size %d != expected stack size 5", stackLen)
+		}
+		checkNoGoroutineLeak(t, before)
+	})
+
+	t.Run("Test many producers and consumers", func(t *testing.T) {
+		// Every sent element must be received exactly once.
+		const gorutinesAmount = 8
+		before := runtime.NumGoroutine()
+		adapter := stackChannels.FreshAdapter[int](context.Background(), traiberStack.FreshTraiberStack[int](), 16)
+
+		sent := make(chan struct{})
+		for g := 0; g < gorutinesAmount; g++ {
+			go func() {
+				defer func() { sent <- struct{}{} }()
+				for i := g; i < tasksAmount; i += gorutinesAmount {
+					adapter.In() <- i
+				}
+			}()
+		}
+		go func() {
+			for g := 0; g < gorutinesAmount; g++ {
+				<-sent
+			}
+			close(adapter.In())
+		}()
+
+		received := make([]chan []int, gorutinesAmount)
+		for g := range received {
+			received[g] = make(chan []int, 1)
+			go func() {
+				var elements []int
+				for elem := range adapter.Out() {
+					elements = append(elements, elem)
+				}
+				received[g] <- elements
+			}()
+		}
+
+		seen := make([]int, tasksAmount)
+		for _, elements := range received {
+			for _, elem := range <-elements {
+				seen[elem]++
+			}
+		}
+		for elem, times := range seen {
+			if times != 1 {
+				t.Fatalf("Element %d was received %d times", elem, times)
+			}
+		}
+		checkNoGoroutineLeak(t, before)
+	})
+}
+
+func checkNoGoroutineLeak(t *testing.T, before int) {
+	// Goroutines need some time to exit after they have finished their work.
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Received %d goroutines != expected at most %d goroutines", after, before)
+	}
+}
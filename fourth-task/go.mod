@@ -1,3 +1,3 @@
 module bst
 
-go 1.22.0
\ No newline at end of file
+go 1.23.0
\ No newline at end of file
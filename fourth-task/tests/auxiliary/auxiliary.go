@@ -2,19 +2,52 @@ package auxiliary
 
 import (
 	"bst/trees"
+	"bst/trees/avlTree"
 	"bst/trees/coarseGrainedTree"
 	"bst/trees/fineGrainedTree"
+	"bst/trees/lockFreeTree"
+	"bst/trees/optimisticAVLTree"
 	"bst/trees/optimisticTree"
+	"bst/trees/redBlackTree"
+	"bst/trees/seqlockTree"
 )
 
 func FreshCoarseGrainedTree() trees.BinarySearchTree[int, int] {
 	return coarseGrainedTree.FreshCoarseGrainedSyncTree[int, int]()
 }
 
+func FreshRWCoarseGrainedTree() trees.BinarySearchTree[int, int] {
+	return coarseGrainedTree.FreshRWCoarseGrainedSyncTree[int, int]()
+}
+
+func FreshSeqlockTree() trees.BinarySearchTree[int, int] {
+	return seqlockTree.FreshSeqlockSyncTree[int, int]()
+}
+
 func FreshFineGrainedTree() trees.BinarySearchTree[int, int] {
 	return fineGrainedTree.FreshFineGrainedSyncTree[int, int]()
 }
 
+func FreshReadCouplingFineGrainedTree() trees.BinarySearchTree[int, int] {
+	return fineGrainedTree.FreshReadCouplingFineGrainedSyncTree[int, int]()
+}
+
 func FreshOptimisticTree() trees.BinarySearchTree[int, int] {
 	return optimisticTree.FreshOptimisticSyncTree[int, int]()
 }
+
+func FreshAVLTree() trees.BinarySearchTree[int, int] {
+	return avlTree.FreshAVLSyncTree[int, int]()
+}
+
+func FreshRedBlackTree() trees.BinarySearchTree[int, int] {
+	return redBlackTree.FreshRedBlackSyncTree[int, int]()
+}
+
+func FreshOptimisticAVLTree() trees.BinarySearchTree[int, int] {
+	return optimisticAVLTree.FreshOptimisticAVLSyncTree[int, int]()
+}
+
+func FreshLockFreeTree() trees.BinarySearchTree[int, int] {
+	return lockFreeTree.FreshLockFreeSyncTree[int, int]()
+}
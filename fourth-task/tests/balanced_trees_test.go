@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"bst/tests/auxiliary"
+	"bst/trees"
+	"math"
+	"sync"
+	"testing"
+)
+
+// In these test cases we check that the self-balancing trees keep a logarithmic height
+// when the keys come in order, which turns the other trees into linked lists.
+
+type heightTree interface {
+	trees.BinarySearchTree[int, int]
+	Height() int
+}
+
+func TestAVLTree(t *testing.T) {
+	runTreesTests(t, auxiliary.FreshAVLTree)
+	runHeightTests(t, auxiliary.FreshAVLTree, func(nodes int) float64 {
+		return 1.44 * math.Log2(float64(nodes+2))
+	})
+}
+
+func TestRedBlackTree(t *testing.T) {
+	runTreesTests(t, auxiliary.FreshRedBlackTree)
+	runHeightTests(t, auxiliary.FreshRedBlackTree, func(nodes int) float64 {
+		return 2 * math.Log2(float64(nodes+1))
+	})
+}
+
+func TestOptimisticAVLTree(t *testing.T) {
+	runTreesTests(t, auxiliary.FreshOptimisticAVLTree)
+	runHeightTests(t, auxiliary.FreshOptimisticAVLTree, func(nodes int) float64 {
+		return 1.44 * math.Log2(float64(nodes+2))
+	})
+}
+
+func runHeightTests(t *testing.T, newTree func() trees.BinarySearchTree[int, int], maxHeight func(int) float64) {
+	const nodesAmount = 100_000
+
+	checkHeight := func(t *testing.T, tree heightTree, nodes int) {
+		t.Helper()
+		if !tree.IsValid() {
+			t.Fatalf("Error: tree is not valid.")
+		}
+		if float64(tree.Height()) > maxHeight(nodes) {
+			t.Errorf("Error: the tree of %d nodes has the height %d, although at most %.1f was expected", nodes, tree.Height(), maxHeight(nodes))
+		}
+	}
+
+	t.Run("Test height after ascending inserts", func(t *testing.T) {
+		tree := newTree().(heightTree)
+		for i := 0; i < nodesAmount; i++ {
+			tree.Insert(i, i)
+		}
+		checkHeight(t, tree, nodesAmount)
+
+		// Removing the smallest half keeps the tree balanced as well.
+		for i := 0; i < nodesAmount/2; i++ {
+			tree.Remove(i)
+		}
+		checkHeight(t, tree, nodesAmount/2)
+	})
+
+	t.Run("Test height after descending inserts", func(t *testing.T) {
+		tree := newTree().(heightTree)
+		for i := nodesAmount; i > 0; i-- {
+			tree.Insert(i, i)
+		}
+		checkHeight(t, tree, nodesAmount)
+
+		for i := 1; i <= nodesAmount; i += 2 {
+			tree.Remove(i)
+		}
+		checkHeight(t, tree, nodesAmount/2)
+	})
+
+	t.Run("Test height after parallel ascending inserts", func(t *testing.T) {
+		const gorutinesAmount = 8
+		tree := newTree().(heightTree)
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for g := 0; g < gorutinesAmount; g++ {
+			go func(g int) {
+				defer wg.Done()
+				for i := g; i < nodesAmount; i += gorutinesAmount {
+					tree.Insert(i, i)
+				}
+			}(g)
+		}
+		wg.Wait()
+		if tree.CountNodes() != nodesAmount {
+			t.Errorf("Error: the tree contains %d nodes, although %d were expected", tree.CountNodes(), nodesAmount)
+		}
+		checkHeight(t, tree, nodesAmount)
+	})
+
+	t.Run("Test height after parallel removes", func(t *testing.T) {
+		// Two of every three keys are removed while the goroutines still insert.
+		const gorutinesAmount = 8
+		tree := newTree().(heightTree)
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for g := 0; g < gorutinesAmount; g++ {
+			go func(g int) {
+				defer wg.Done()
+				for i := g; i < nodesAmount; i += gorutinesAmount {
+					tree.Insert(i, i)
+					if i >= 10*gorutinesAmount && (i-10*gorutinesAmount)%3 != 0 {
+						tree.Remove(i - 10*gorutinesAmount)
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+		for i := nodesAmount - 10*gorutinesAmount; i < nodesAmount; i++ {
+			if i%3 != 0 {
+				tree.Remove(i)
+			}
+		}
+		if tree.CountNodes() != (nodesAmount+2)/3 {
+			t.Errorf("Error: the tree contains %d nodes, although %d were expected", tree.CountNodes(), (nodesAmount+2)/3)
+		}
+		checkHeight(t, tree, (nodesAmount+2)/3)
+	})
+}
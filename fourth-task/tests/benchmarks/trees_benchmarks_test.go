@@ -0,0 +1,49 @@
+package benchmarks
+
+import (
+	"bst/tests/auxiliary"
+	"bst/trees/treetest"
+	"testing"
+)
+
+// Metrics are measured for every tree with the scenarios from treetest.
+
+func BenchmarkCoarseGrainedTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshCoarseGrainedTree))
+}
+
+func BenchmarkRWCoarseGrainedTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshRWCoarseGrainedTree))
+}
+
+func BenchmarkSeqlockTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshSeqlockTree))
+}
+
+func BenchmarkFineGrainedTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshFineGrainedTree))
+}
+
+func BenchmarkReadCouplingFineGrainedTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshReadCouplingFineGrainedTree))
+}
+
+func BenchmarkOptimisticTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshOptimisticTree))
+}
+
+func BenchmarkAVLTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshAVLTree))
+}
+
+func BenchmarkRedBlackTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshRedBlackTree))
+}
+
+func BenchmarkOptimisticAVLTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshOptimisticAVLTree))
+}
+
+func BenchmarkLockFreeTree(b *testing.B) {
+	treetest.Benchmark(b, treetest.Ints(auxiliary.FreshLockFreeTree))
+}
@@ -0,0 +1,385 @@
+package avlTree
+
+import (
+	"bst/trees"
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+)
+
+// AVL tree behind a single mutex, like CoarseGrainedSyncTree. The heights of the subtrees
+// differ by at most one, so the height of the tree stays below 1.44 * log2(n + 2).
+
+type AVLSyncTree[T any, K cmp.Ordered] struct {
+	root  *Node[T, K]
+	mutex sync.Mutex
+}
+
+type Node[T any, K cmp.Ordered] struct {
+	key    K
+	value  T
+	left   *Node[T, K]
+	right  *Node[T, K]
+	height int // The amount of nodes on the longest path down from the node.
+	size   int // The amount of nodes in the subtree.
+}
+
+func FreshAVLSyncTree[T any, K cmp.Ordered]() *AVLSyncTree[T, K] {
+	return &AVLSyncTree[T, K]{
+		root:  nil,
+		mutex: sync.Mutex{},
+	}
+}
+
+func (tree *AVLSyncTree[T, K]) lock() {
+	tree.mutex.Lock()
+}
+
+func (tree *AVLSyncTree[T, K]) unlock() {
+	tree.mutex.Unlock()
+}
+
+func (tree *AVLSyncTree[T, K]) Insert(key K, value T) {
+	if tree == nil {
+		return
+	}
+	tree.lock()
+	defer tree.unlock()
+	tree.root = tree.insertRecursive(tree.root, key, value)
+}
+
+func (tree *AVLSyncTree[T, K]) insertRecursive(node *Node[T, K], key K, value T) *Node[T, K] {
+	if node == nil {
+		return &Node[T, K]{key: key, value: value, height: 1, size: 1}
+	}
+	comp := cmp.Compare(key, node.key)
+	if comp == -1 {
+		node.left = tree.insertRecursive(node.left, key, value)
+	} else if comp == 1 {
+		node.right = tree.insertRecursive(node.right, key, value)
+	} else {
+		// Key already exists, update value
+		node.value = value
+		return node
+	}
+	return node.balance()
+}
+
+func (tree *AVLSyncTree[T, K]) Find(key K) (T, bool) {
+	if tree == nil {
+		return *(new(T)), false
+	}
+	tree.lock()
+	defer tree.unlock()
+	value, found := tree.findRecursive(tree.root, key)
+	return value, found
+}
+
+func (tree *AVLSyncTree[T, K]) findRecursive(node *Node[T, K], key K) (T, bool) {
+	var emptValue T
+	if node == nil {
+		return emptValue, false
+	}
+	comp := cmp.Compare(key, node.key)
+	if comp == -1 {
+		return tree.findRecursive(node.left, key)
+	} else if comp == 1 {
+		return tree.findRecursive(node.right, key)
+	} else {
+		return node.value, true
+	}
+}
+
+func (tree *AVLSyncTree[T, K]) Remove(key K) bool {
+	if tree == nil {
+		return false
+	}
+	tree.lock()
+	defer tree.unlock()
+	var removed bool
+	tree.root, removed = tree.removeRecursive(tree.root, key)
+	return removed
+}
+
+func (tree *AVLSyncTree[T, K]) removeRecursive(node *Node[T, K], key K) (*Node[T, K], bool) {
+	if node == nil {
+		return nil, false
+	}
+	var removed bool
+	comp := cmp.Compare(key, node.key)
+	if comp == -1 {
+		node.left, removed = tree.removeRecursive(node.left, key)
+	} else if comp == 1 {
+		node.right, removed = tree.removeRecursive(node.right, key)
+	} else {
+		// Node to be deleted found
+		if node.left == nil {
+			return node.right, true
+		} else if node.right == nil {
+			return node.left, true
+		}
+		// Copy the inorder successor's content to this node and delete the successor
+		successor := tree.minValueNode(node.right)
+		node.key = successor.key
+		node.value = successor.value
+		node.right, _ = tree.removeRecursive(node.right, successor.key)
+		removed = true
+	}
+	if !removed {
+		return node, false
+	}
+	return node.balance(), true
+}
+
+func (tree *AVLSyncTree[T, K]) minValueNode(node *Node[T, K]) *Node[T, K] {
+	current := node
+	for current.left != nil {
+		current = current.left
+	}
+	return current
+}
+
+// Rebalancing. Every node on the path of an insertion or a removal is balanced on the way up.
+
+func (node *Node[T, K]) balance() *Node[T, K] {
+	// Restores the AVL property of the node, whose subtrees are already balanced
+	node.update()
+	factor := node.left.heightOf() - node.right.heightOf()
+	if factor > 1 {
+		if node.left.left.heightOf() < node.left.right.heightOf() {
+			node.left = node.left.rotateLeft()
+		}
+		return node.rotateRight()
+	}
+	if factor < -1 {
+		if node.right.right.heightOf() < node.right.left.heightOf() {
+			node.right = node.right.rotateRight()
+		}
+		return node.rotateLeft()
+	}
+	return node
+}
+
+func (node *Node[T, K]) rotateLeft() *Node[T, K] {
+	right := node.right
+	node.right = right.left
+	right.left = node
+	node.update()
+	right.update()
+	return right
+}
+
+func (node *Node[T, K]) rotateRight() *Node[T, K] {
+	left := node.left
+	node.left = left.right
+	left.right = node
+	node.update()
+	left.update()
+	return left
+}
+
+func (node *Node[T, K]) update() {
+	node.height = 1 + max(node.left.heightOf(), node.right.heightOf())
+	node.size = 1 + node.left.Size() + node.right.Size()
+}
+
+func (node *Node[T, K]) heightOf() int {
+	if node == nil {
+		return 0
+	}
+	return node.height
+}
+
+func (tree *AVLSyncTree[T, K]) Print() {
+	if tree == nil {
+		return
+	}
+	tree.mutex.Lock()
+	defer tree.mutex.Unlock()
+
+	tree.printHelper(tree.root, 0)
+}
+
+func (tree *AVLSyncTree[T, K]) printHelper(node *Node[T, K], indent int) {
+	if node == nil {
+		return
+	}
+
+	// Print the right subtree with indentation
+	tree.printHelper(node.right, indent+4)
+
+	// Print current node
+	fmt.Print(strings.Repeat(" ", indent))
+	fmt.Printf("%v\n", node.key)
+
+	// Print left subtree with indentation
+	tree.printHelper(node.left, indent+4)
+}
+
+func (tree *AVLSyncTree[T, K]) CountNodes() int {
+	// CountNodes counts the number of nodes in the tree
+	if tree == nil {
+		return 0
+	}
+	tree.lock()
+	defer tree.unlock()
+	return tree.countNodesRecursive(tree.root)
+}
+
+func (tree *AVLSyncTree[T, K]) countNodesRecursive(node *Node[T, K]) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + tree.countNodesRecursive(node.left) + tree.countNodesRecursive(node.right)
+}
+
+func (tree *AVLSyncTree[T, K]) Height() int {
+	// Height returns the amount of nodes on the longest path from the root
+	if tree == nil {
+		return 0
+	}
+	tree.lock()
+	defer tree.unlock()
+	return tree.root.heightOf()
+}
+
+func (tree *AVLSyncTree[T, K]) IsValid() bool {
+	// IsValid checks if the tree is a valid binary search tree with balanced heights
+	if tree == nil {
+		return true
+	}
+	tree.lock()
+	defer tree.unlock()
+	_, validHeights := tree.isValidHeightRecursive(tree.root)
+	return tree.isValidBSTRecursive(tree.root, nil, nil) && trees.ValidSizes(tree.root) && validHeights
+}
+
+func (tree *AVLSyncTree[T, K]) isValidBSTRecursive(node *Node[T, K], min *K, max *K) bool {
+	if node == nil {
+		return true
+	}
+	if (min != nil && cmp.Compare(node.key, *min) <= 0) || (max != nil && cmp.Compare(node.key, *max) >= 0) {
+		return false
+	}
+	return tree.isValidBSTRecursive(node.left, min, &node.key) && tree.isValidBSTRecursive(node.right, &node.key, max)
+}
+
+func (tree *AVLSyncTree[T, K]) isValidHeightRecursive(node *Node[T, K]) (int, bool) {
+	// Returns the real height of the subtree and whether it is balanced and all the stored heights match
+	if node == nil {
+		return 0, true
+	}
+	left, validLeft := tree.isValidHeightRecursive(node.left)
+	right, validRight := tree.isValidHeightRecursive(node.right)
+	height := 1 + max(left, right)
+	return height, validLeft && validRight && node.height == height && left-right <= 1 && right-left <= 1
+}
+
+// Iteration. The pairs are copied under the tree mutex and yielded after it is released,
+// so every iteration sees an atomic snapshot of the tree and the loop body may use the tree freely.
+
+func (tree *AVLSyncTree[T, K]) Range(lo, hi K) iter.Seq2[K, T] {
+	return tree.snapshot(&lo, &hi)
+}
+
+func (tree *AVLSyncTree[T, K]) All() iter.Seq2[K, T] {
+	return tree.snapshot(nil, nil)
+}
+
+func (tree *AVLSyncTree[T, K]) Keys() iter.Seq[K] {
+	return trees.KeysOf(tree.All())
+}
+
+func (tree *AVLSyncTree[T, K]) Values() iter.Seq[T] {
+	return trees.ValuesOf(tree.All())
+}
+
+func (tree *AVLSyncTree[T, K]) snapshot(lo, hi *K) iter.Seq2[K, T] {
+	return trees.Snapshot(func() []trees.Pair[T, K] {
+		if tree == nil {
+			return nil
+		}
+		tree.lock()
+		defer tree.unlock()
+		return trees.Collect(tree.root, lo, hi)
+	})
+}
+
+// Ordered queries.
+
+func (tree *AVLSyncTree[T, K]) Min() (K, T, bool) {
+	return tree.nearest(nil, true, true)
+}
+
+func (tree *AVLSyncTree[T, K]) Max() (K, T, bool) {
+	return tree.nearest(nil, true, false)
+}
+
+func (tree *AVLSyncTree[T, K]) Floor(key K) (K, T, bool) {
+	return tree.nearest(&key, true, false)
+}
+
+func (tree *AVLSyncTree[T, K]) Ceiling(key K) (K, T, bool) {
+	return tree.nearest(&key, true, true)
+}
+
+func (tree *AVLSyncTree[T, K]) Predecessor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, false)
+}
+
+func (tree *AVLSyncTree[T, K]) Successor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, true)
+}
+
+func (tree *AVLSyncTree[T, K]) nearest(bound *K, inclusive, ascending bool) (K, T, bool) {
+	// The closest key beyond bound in the given direction
+	if tree == nil {
+		return *(new(K)), *(new(T)), false
+	}
+	tree.lock()
+	defer tree.unlock()
+	return trees.Nearest(tree.root, bound, inclusive, ascending)
+}
+
+// Order statistics. The subtree sizes are kept by Insert and Remove, so both queries take O(height).
+
+func (tree *AVLSyncTree[T, K]) Rank(key K) int {
+	if tree == nil {
+		return 0
+	}
+	tree.lock()
+	defer tree.unlock()
+	return trees.Rank(tree.root, key)
+}
+
+func (tree *AVLSyncTree[T, K]) Select(rank int) (K, T, bool) {
+	if tree == nil {
+		return *(new(K)), *(new(T)), false
+	}
+	tree.lock()
+	defer tree.unlock()
+	return trees.Select(tree.root, rank)
+}
+
+// The accessors for the helpers of package trees.
+
+func (node *Node[T, K]) Pair() (K, T) {
+	return node.key, node.value
+}
+
+func (node *Node[T, K]) Left() *Node[T, K] {
+	return node.left
+}
+
+func (node *Node[T, K]) Right() *Node[T, K] {
+	return node.right
+}
+
+func (node *Node[T, K]) Size() int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
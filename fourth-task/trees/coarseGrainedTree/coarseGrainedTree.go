@@ -1,15 +1,18 @@
 package coarseGrainedTree
 
 import (
+	"bst/trees"
 	"cmp"
 	"fmt"
+	"iter"
 	"strings"
 	"sync"
 )
 
 type CoarseGrainedSyncTree[T any, K cmp.Ordered] struct {
-	root  *Node[T, K]
-	mutex sync.Mutex
+	root   *Node[T, K]
+	mutex  sync.RWMutex
+	shared bool // Whether the queries take the mutex for reading, so that they run in parallel.
 }
 
 type Node[T any, K cmp.Ordered] struct {
@@ -17,12 +20,22 @@ type Node[T any, K cmp.Ordered] struct {
 	value T
 	left  *Node[T, K]
 	right *Node[T, K]
+	size  int // The amount of nodes in the subtree.
 }
 
 func FreshCoarseGrainedSyncTree[T any, K cmp.Ordered]() *CoarseGrainedSyncTree[T, K] {
 	return &CoarseGrainedSyncTree[T, K]{
 		root:  nil,
-		mutex: sync.Mutex{},
+		mutex: sync.RWMutex{},
+	}
+}
+
+func FreshRWCoarseGrainedSyncTree[T any, K cmp.Ordered]() *CoarseGrainedSyncTree[T, K] {
+	// The read-optimized variant, which serializes only the writers
+	return &CoarseGrainedSyncTree[T, K]{
+		root:   nil,
+		mutex:  sync.RWMutex{},
+		shared: true,
 	}
 }
 
@@ -34,38 +47,68 @@ func (tree *CoarseGrainedSyncTree[T, K]) unlock() {
 	tree.mutex.Unlock()
 }
 
+func (tree *CoarseGrainedSyncTree[T, K]) rlock() {
+	if tree.shared {
+		tree.mutex.RLock()
+	} else {
+		tree.mutex.Lock()
+	}
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) runlock() {
+	if tree.shared {
+		tree.mutex.RUnlock()
+	} else {
+		tree.mutex.Unlock()
+	}
+}
+
 func (tree *CoarseGrainedSyncTree[T, K]) Insert(key K, value T) {
+	if tree == nil {
+		return
+	}
 	tree.lock()
 	defer tree.unlock()
 	if tree.root == nil {
-		tree.root = &Node[T, K]{key: key, value: value}
+		tree.root = &Node[T, K]{key: key, value: value, size: 1}
 		return
 	}
 	tree.insertRecursive(tree.root, key, value)
 }
 
-func (tree *CoarseGrainedSyncTree[T, K]) insertRecursive(node *Node[T, K], key K, value T) {
+func (tree *CoarseGrainedSyncTree[T, K]) insertRecursive(node *Node[T, K], key K, value T) bool {
+	// Returns whether a new node was added
+	inserted := false
 	if cmp.Less(key, node.key) {
 		if node.left == nil {
-			node.left = &Node[T, K]{key: key, value: value}
+			node.left = &Node[T, K]{key: key, value: value, size: 1}
+			inserted = true
 		} else {
-			tree.insertRecursive(node.left, key, value)
+			inserted = tree.insertRecursive(node.left, key, value)
 		}
 	} else if cmp.Compare(key, node.key) == 1 {
 		if node.right == nil {
-			node.right = &Node[T, K]{key: key, value: value}
+			node.right = &Node[T, K]{key: key, value: value, size: 1}
+			inserted = true
 		} else {
-			tree.insertRecursive(node.right, key, value)
+			inserted = tree.insertRecursive(node.right, key, value)
 		}
 	} else {
 		// Key already exists, update value
 		node.value = value
 	}
+	if inserted {
+		node.size++
+	}
+	return inserted
 }
 
 func (tree *CoarseGrainedSyncTree[T, K]) Find(key K) (T, bool) {
-	tree.lock()
-	defer tree.unlock()
+	if tree == nil {
+		return *(new(T)), false
+	}
+	tree.rlock()
+	defer tree.runlock()
 	value, found := tree.findRecursive(tree.root, key)
 	return value, found
 }
@@ -86,9 +129,13 @@ func (tree *CoarseGrainedSyncTree[T, K]) findRecursive(node *Node[T, K], key K)
 }
 
 func (tree *CoarseGrainedSyncTree[T, K]) Remove(key K) bool {
+	if tree == nil {
+		return false
+	}
 	tree.lock()
 	defer tree.unlock()
-	_, removed := tree.removeRecursive(tree.root, key)
+	var removed bool
+	tree.root, removed = tree.removeRecursive(tree.root, key)
 	return removed
 }
 
@@ -100,9 +147,15 @@ func (tree *CoarseGrainedSyncTree[T, K]) removeRecursive(node *Node[T, K], key K
 	comp := cmp.Compare(key, node.key)
 	if comp == -1 {
 		node.left, removed = tree.removeRecursive(node.left, key)
+		if removed {
+			node.size--
+		}
 		return node, removed
 	} else if comp == 1 {
 		node.right, removed = tree.removeRecursive(node.right, key)
+		if removed {
+			node.size--
+		}
 		return node, removed
 	} else {
 		// Node to be deleted found
@@ -119,6 +172,7 @@ func (tree *CoarseGrainedSyncTree[T, K]) removeRecursive(node *Node[T, K], key K
 			node.value = successor.value
 			// Delete the inorder successor
 			node.right, _ = tree.removeRecursive(node.right, successor.key)
+			node.size--
 			return node, true
 		}
 	}
@@ -133,8 +187,11 @@ func (tree *CoarseGrainedSyncTree[T, K]) minValueNode(node *Node[T, K]) *Node[T,
 }
 
 func (tree *CoarseGrainedSyncTree[T, K]) Print() {
-	tree.mutex.Lock()
-	defer tree.mutex.Unlock()
+	if tree == nil {
+		return
+	}
+	tree.rlock()
+	defer tree.runlock()
 
 	tree.printHelper(tree.root, 0)
 }
@@ -157,8 +214,11 @@ func (tree *CoarseGrainedSyncTree[T, K]) printHelper(node *Node[T, K], indent in
 
 func (tree *CoarseGrainedSyncTree[T, K]) CountNodes() int {
 	// CountNodes counts the number of nodes in the tree
-	tree.lock()
-	defer tree.unlock()
+	if tree == nil {
+		return 0
+	}
+	tree.rlock()
+	defer tree.runlock()
 	return tree.countNodesRecursive(tree.root)
 }
 
@@ -171,9 +231,12 @@ func (tree *CoarseGrainedSyncTree[T, K]) countNodesRecursive(node *Node[T, K]) i
 
 func (tree *CoarseGrainedSyncTree[T, K]) IsValid() bool {
 	// IsValid checks if the tree is a valid binary search tree
-	tree.lock()
-	defer tree.unlock()
-	return tree.isValidBSTRecursive(tree.root, nil, nil)
+	if tree == nil {
+		return true
+	}
+	tree.rlock()
+	defer tree.runlock()
+	return tree.isValidBSTRecursive(tree.root, nil, nil) && trees.ValidSizes(tree.root)
 }
 
 func (tree *CoarseGrainedSyncTree[T, K]) isValidBSTRecursive(node *Node[T, K], min *K, max *K) bool {
@@ -185,3 +248,110 @@ func (tree *CoarseGrainedSyncTree[T, K]) isValidBSTRecursive(node *Node[T, K], m
 	}
 	return tree.isValidBSTRecursive(node.left, min, &node.key) && tree.isValidBSTRecursive(node.right, &node.key, max)
 }
+
+// Iteration. The pairs are copied under the tree mutex and yielded after it is released,
+// so every iteration sees an atomic snapshot of the tree and the loop body may use the tree freely.
+
+func (tree *CoarseGrainedSyncTree[T, K]) Range(lo, hi K) iter.Seq2[K, T] {
+	return tree.snapshot(&lo, &hi)
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) All() iter.Seq2[K, T] {
+	return tree.snapshot(nil, nil)
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) Keys() iter.Seq[K] {
+	return trees.KeysOf(tree.All())
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) Values() iter.Seq[T] {
+	return trees.ValuesOf(tree.All())
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) snapshot(lo, hi *K) iter.Seq2[K, T] {
+	return trees.Snapshot(func() []trees.Pair[T, K] {
+		if tree == nil {
+			return nil
+		}
+		tree.rlock()
+		defer tree.runlock()
+		return trees.Collect(tree.root, lo, hi)
+	})
+}
+
+// Ordered queries.
+
+func (tree *CoarseGrainedSyncTree[T, K]) Min() (K, T, bool) {
+	return tree.nearest(nil, true, true)
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) Max() (K, T, bool) {
+	return tree.nearest(nil, true, false)
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) Floor(key K) (K, T, bool) {
+	return tree.nearest(&key, true, false)
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) Ceiling(key K) (K, T, bool) {
+	return tree.nearest(&key, true, true)
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) Predecessor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, false)
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) Successor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, true)
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) nearest(bound *K, inclusive, ascending bool) (K, T, bool) {
+	// The closest key beyond bound in the given direction
+	if tree == nil {
+		return *(new(K)), *(new(T)), false
+	}
+	tree.rlock()
+	defer tree.runlock()
+	return trees.Nearest(tree.root, bound, inclusive, ascending)
+}
+
+// Order statistics. The subtree sizes are kept by Insert and Remove, so both queries take O(height).
+
+func (tree *CoarseGrainedSyncTree[T, K]) Rank(key K) int {
+	if tree == nil {
+		return 0
+	}
+	tree.rlock()
+	defer tree.runlock()
+	return trees.Rank(tree.root, key)
+}
+
+func (tree *CoarseGrainedSyncTree[T, K]) Select(rank int) (K, T, bool) {
+	if tree == nil {
+		return *(new(K)), *(new(T)), false
+	}
+	tree.rlock()
+	defer tree.runlock()
+	return trees.Select(tree.root, rank)
+}
+
+// The accessors for the helpers of package trees.
+
+func (node *Node[T, K]) Pair() (K, T) {
+	return node.key, node.value
+}
+
+func (node *Node[T, K]) Left() *Node[T, K] {
+	return node.left
+}
+
+func (node *Node[T, K]) Right() *Node[T, K] {
+	return node.right
+}
+
+func (node *Node[T, K]) Size() int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
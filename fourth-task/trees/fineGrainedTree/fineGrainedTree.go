@@ -3,31 +3,61 @@ package fineGrainedTree
 import "cmp"
 
 import (
+	"bst/trees"
 	"fmt"
+	"iter"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 type FineGrainedSyncTree[T any, K cmp.Ordered] struct {
-	root  *Node[T, K]
-	mutex sync.Mutex
+	root         atomic.Pointer[Node[T, K]]
+	mutex        sync.RWMutex
+	relocations  trees.Relocations
+	readCoupling bool // Whether the searches couple read locks instead of exclusive ones.
 }
 
+// Writers change the nodes under their locks, but Find reads them without any,
+// so the links are atomic and a moved successor replaces the whole pair at once.
 type Node[T any, K cmp.Ordered] struct {
+	pair    atomic.Pointer[pair[T, K]]
+	left    atomic.Pointer[Node[T, K]]
+	right   atomic.Pointer[Node[T, K]]
+	mutex   sync.RWMutex
+	size    atomic.Int64 // The amount of nodes in the subtree.
+	removed atomic.Bool  // Set under the node lock, before the node is unlinked from the tree.
+}
+
+type pair[T any, K cmp.Ordered] struct {
 	key   K
 	value T
-	left  *Node[T, K]
-	right *Node[T, K]
-	mutex sync.Mutex
 }
 
 func FreshFineGrainedSyncTree[T any, K cmp.Ordered]() *FineGrainedSyncTree[T, K] {
 	return &FineGrainedSyncTree[T, K]{
-		root:  nil,
-		mutex: sync.Mutex{},
+		mutex: sync.RWMutex{},
+	}
+}
+
+func FreshReadCouplingFineGrainedSyncTree[T any, K cmp.Ordered]() *FineGrainedSyncTree[T, K] {
+	// In this mode the searches on the same path do not exclude each other. Insert and Remove
+	// search with read locks as well and then lock the last two nodes for writing,
+	// so only the parent and the child of the change are held exclusively.
+	return &FineGrainedSyncTree[T, K]{
+		mutex:        sync.RWMutex{},
+		readCoupling: true,
 	}
 }
 
+func (node *Node[T, K]) key() K {
+	return node.pair.Load().key
+}
+
+func (node *Node[T, K]) value() T {
+	return node.pair.Load().value
+}
+
 func (tree *FineGrainedSyncTree[T, K]) lock() {
 	tree.mutex.Lock()
 }
@@ -44,79 +74,196 @@ func (node *Node[T, K]) unlock() {
 	node.mutex.Unlock()
 }
 
-func (tree *FineGrainedSyncTree[T, K]) findWithParent(key K) (*Node[T, K], *Node[T, K]) {
-	tree.lock()
+func (tree *FineGrainedSyncTree[T, K]) acquire(shared bool) {
+	if shared {
+		tree.mutex.RLock()
+	} else {
+		tree.mutex.Lock()
+	}
+}
+
+func (tree *FineGrainedSyncTree[T, K]) release(shared bool) {
+	if shared {
+		tree.mutex.RUnlock()
+	} else {
+		tree.mutex.Unlock()
+	}
+}
+
+func (node *Node[T, K]) acquire(shared bool) {
+	if shared {
+		node.mutex.RLock()
+	} else {
+		node.mutex.Lock()
+	}
+}
 
-	if tree.root != nil {
-		tree.root.lock()
+func (node *Node[T, K]) release(shared bool) {
+	if shared {
+		node.mutex.RUnlock()
+	} else {
+		node.mutex.Unlock()
+	}
+}
+
+func (tree *FineGrainedSyncTree[T, K]) releaseFound(node, parent *Node[T, K], shared bool) {
+	// Unlocks the result of findWithParent
+	if node != nil {
+		node.release(shared)
+	}
+	if parent != nil {
+		parent.release(shared)
+	} else {
+		tree.release(shared)
+	}
+}
+
+func (tree *FineGrainedSyncTree[T, K]) findWithParent(key K, path *[]*Node[T, K], shared bool) (*Node[T, K], *Node[T, K]) {
+	// The ancestors of the found node are appended to path, unless it is nil.
+	// The locks are taken for reading, if shared
+	tree.acquire(shared)
+
+	if tree.root.Load() != nil {
+		tree.root.Load().acquire(shared)
 	}
 
 	parent := (*Node[T, K])(nil)
-	current := tree.root
-	for current != nil && current.key != key {
-		if cmp.Less(key, current.key) {
-			if current.left != nil {
-				current.left.lock()
+	current := tree.root.Load()
+	for current != nil && current.key() != key {
+		if cmp.Less(key, current.key()) {
+			if current.left.Load() != nil {
+				current.left.Load().acquire(shared)
 			}
 			if parent != nil {
-				parent.unlock()
+				parent.release(shared)
 			} else {
-				tree.unlock()
+				tree.release(shared)
 			}
 			parent = current
-			current = current.left
+			if path != nil {
+				*path = append(*path, current)
+			}
+			current = current.left.Load()
 		} else {
-			if current.right != nil {
-				current.right.lock()
+			if current.right.Load() != nil {
+				current.right.Load().acquire(shared)
 			}
 			if parent != nil {
-				parent.unlock()
+				parent.release(shared)
 			} else {
-				tree.unlock()
+				tree.release(shared)
 			}
 			parent = current
-			current = current.right
+			if path != nil {
+				*path = append(*path, current)
+			}
+			current = current.right.Load()
 		}
 	}
 
 	return current, parent
 }
 
+func (tree *FineGrainedSyncTree[T, K]) findForUpdate(key K, path *[]*Node[T, K]) (*Node[T, K], *Node[T, K]) {
+	// Same as findWithParent, but the found node and its parent are always locked for writing
+	if !tree.readCoupling {
+		return tree.findWithParent(key, path, false)
+	}
+	for {
+		epoch := tree.relocations.Wait()
+
+		*path = (*path)[:0]
+		current, parent := tree.findWithParent(key, path, true)
+
+		// Trade the read locks for write locks, the nodes may change in between. Nothing is held
+		// while waiting, except the parent above the child, so the locks are still taken top-down
+		// as in the successor locking of Remove
+		tree.releaseFound(current, parent, true)
+		if parent != nil {
+			parent.lock()
+		} else {
+			tree.lock()
+		}
+		if current != nil {
+			current.lock()
+		}
+
+		// Without relocations a key keeps its path, unless a node of the path is unlinked
+		valid := tree.relocations.Unchanged(epoch) && (current == nil || current.key() == key)
+		if parent == nil {
+			valid = valid && tree.root.Load() == current
+		} else if cmp.Less(key, parent.key()) {
+			valid = valid && !parent.removed.Load() && parent.left.Load() == current
+		} else {
+			valid = valid && !parent.removed.Load() && parent.right.Load() == current
+		}
+		if valid {
+			return current, parent
+		}
+		tree.releaseFound(current, parent, false)
+	}
+}
+
 func (tree *FineGrainedSyncTree[T, K]) Find(key K) (T, bool) {
+	// Find does not lock on its fast path. Its linearization point is the load of the found pair:
+	// Remove marks a node before unlinking it, so an unmarked node was linked at that moment.
+	// A miss is exact only if no successor moved during the search, otherwise findLocked repeats it
+	if tree == nil {
+		return *(new(T)), false
+	}
+	epoch := tree.relocations.Epoch()
+	current := tree.root.Load()
+	for current != nil {
+		pair := current.pair.Load()
+		if pair.key == key {
+			if !current.removed.Load() {
+				return pair.value, true
+			}
+			break
+		}
+		if cmp.Less(key, pair.key) {
+			current = current.left.Load()
+		} else {
+			current = current.right.Load()
+		}
+	}
+	if current == nil && tree.relocations.Unchanged(epoch) {
+		return *(new(T)), false
+	}
+	return tree.findLocked(key)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) findLocked(key K) (T, bool) {
 	// Use the findWithParent helper method to find a node and its parent
-	node, parent := tree.findWithParent(key)
+	shared := tree.readCoupling
+	node, parent := tree.findWithParent(key, nil, shared)
 
 	// If the current node is null, then the key was not found
 	if node == nil {
-		if parent != nil {
-			parent.unlock()
-		} else {
-			tree.unlock()
-		}
+		tree.releaseFound(node, parent, shared)
 		var zeroValue T
 		return zeroValue, false
 	}
 
-	value := node.value
+	value := node.value()
 
 	// Removing blocking
-	if parent != nil {
-		parent.unlock()
-	} else {
-		tree.unlock()
-	}
-	node.unlock()
+	tree.releaseFound(node, parent, shared)
 
 	return value, true
 }
 
 func (tree *FineGrainedSyncTree[T, K]) Insert(key K, value T) {
-	// Use the findWithParent helper method to find a node and its parent
-	node, parent := tree.findWithParent(key)
+	if tree == nil {
+		return
+	}
+	// Use the findForUpdate helper method to find a node and its parent
+	path := []*Node[T, K]{}
+	node, parent := tree.findForUpdate(key, &path)
 
 	// If a node with such a key already exists, update its value
 	if node != nil {
-		node.value = value
+		node.pair.Store(&pair[T, K]{key: key, value: value})
 		node.unlock()
 		if parent != nil {
 			parent.unlock()
@@ -126,25 +273,23 @@ func (tree *FineGrainedSyncTree[T, K]) Insert(key K, value T) {
 		return
 	}
 
-	newNode := &Node[T, K]{
-		key:   key,
-		value: value,
-		left:  nil,
-		right: nil,
-	}
+	newNode := &Node[T, K]{}
+	newNode.pair.Store(&pair[T, K]{key: key, value: value})
+	newNode.size.Store(1)
 
 	// Inserting a new node into the tree
 	if parent == nil {
 		// The tree is empty, insert a new node as the root
-		tree.root = newNode
+		tree.root.Store(newNode)
 		tree.unlock()
 	} else {
 		// Insert a new node as a child of the parent
-		if cmp.Less(key, parent.key) {
-			parent.left = newNode
+		if cmp.Less(key, parent.key()) {
+			parent.left.Store(newNode)
 		} else {
-			parent.right = newNode
+			parent.right.Store(newNode)
 		}
+		grow(path, 1)
 		parent.unlock()
 	}
 
@@ -152,8 +297,12 @@ func (tree *FineGrainedSyncTree[T, K]) Insert(key K, value T) {
 }
 
 func (tree *FineGrainedSyncTree[T, K]) Remove(key K) bool {
-	// Use the findWithParent helper method to find a node and its parent
-	node, parent := tree.findWithParent(key)
+	if tree == nil {
+		return false
+	}
+	// Use the findForUpdate helper method to find a node and its parent
+	path := []*Node[T, K]{}
+	node, parent := tree.findForUpdate(key, &path)
 
 	// If the node is not found, we do nothing
 	if node == nil {
@@ -175,62 +324,75 @@ func (tree *FineGrainedSyncTree[T, K]) Remove(key K) bool {
 	}()
 
 	// If the node has no children
-	if node.left == nil && node.right == nil {
+	if node.left.Load() == nil && node.right.Load() == nil {
+		node.removed.Store(true)
 		if parent == nil {
-			tree.root = nil
-		} else if cmp.Less(key, parent.key) {
-			parent.left = nil
+			tree.root.Store(nil)
+		} else if cmp.Less(key, parent.key()) {
+			parent.left.Store(nil)
 		} else {
-			parent.right = nil
+			parent.right.Store(nil)
 		}
+		grow(path, -1)
 		return true
 	}
 
 	// If a node has one child
-	if node.left == nil || node.right == nil {
+	if node.left.Load() == nil || node.right.Load() == nil {
 		var child *Node[T, K]
-		if node.left != nil {
-			child = node.left
+		if node.left.Load() != nil {
+			child = node.left.Load()
 		} else {
-			child = node.right
+			child = node.right.Load()
 		}
+		node.removed.Store(true)
 
 		if parent == nil {
-			tree.root = child
-		} else if cmp.Less(key, parent.key) {
-			parent.left = child
+			tree.root.Store(child)
+		} else if cmp.Less(key, parent.key()) {
+			parent.left.Store(child)
 		} else {
-			parent.right = child
+			parent.right.Store(child)
 		}
+		grow(path, -1)
 		return true
 	}
 
 	// If a node has two children
-	if node.left != nil && node.right != nil {
+	if node.left.Load() != nil && node.right.Load() != nil {
 		successorParent := node
-		successor := node.right
+		successor := node.right.Load()
 		successor.lock()
 
-		for successor.left != nil {
-			successor.left.lock()
+		// Every node between the removed one and the successor loses the successor
+		grow(path, -1)
+		node.size.Add(-1)
+		for successor.left.Load() != nil {
+			successor.size.Add(-1)
+			successor.left.Load().lock()
 			if successorParent != node {
 				successorParent.unlock()
 			}
 			successorParent = successor
-			successor = successor.left
+			successor = successor.left.Load()
 		}
 
+		// The successor parent is released only after the move is finished, an update
+		// that validated against it meanwhile could put a key on the wrong side of the node
+		tree.relocations.Begin()
+		successor.removed.Store(true)
 		if successorParent != node {
-			successorParent.left = successor.right
-			node.key = successor.key
-			node.value = successor.value
-			successorParent.unlock()
+			successorParent.left.Store(successor.right.Load())
 		} else {
-			successorParent.right = successor.right
-			node.key = successor.key
-			node.value = successor.value
+			successorParent.right.Store(successor.right.Load())
 		}
+		node.pair.Store(successor.pair.Load())
+		tree.relocations.End()
 
+		if successorParent != node {
+			successorParent.unlock()
+		}
+		successor.unlock()
 		return true
 	}
 
@@ -238,10 +400,13 @@ func (tree *FineGrainedSyncTree[T, K]) Remove(key K) bool {
 }
 
 func (tree *FineGrainedSyncTree[T, K]) Print() {
+	if tree == nil {
+		return
+	}
 	tree.lock()
 	defer tree.unlock()
 
-	tree.printHelper(tree.root, 0)
+	tree.printHelper(tree.root.Load(), 0)
 }
 
 func (tree *FineGrainedSyncTree[T, K]) printHelper(node *Node[T, K], indent int) {
@@ -250,43 +415,323 @@ func (tree *FineGrainedSyncTree[T, K]) printHelper(node *Node[T, K], indent int)
 	}
 
 	// Print the right subtree with indentation
-	tree.printHelper(node.right, indent+4)
+	tree.printHelper(node.right.Load(), indent+4)
 
 	// Print current node
 	fmt.Print(strings.Repeat(" ", indent))
-	fmt.Printf("%v\n", node.key)
+	fmt.Printf("%v\n", node.key())
 
 	// Print left subtree with indentation
-	tree.printHelper(node.left, indent+4)
+	tree.printHelper(node.left.Load(), indent+4)
 }
 
 func (tree *FineGrainedSyncTree[T, K]) CountNodes() int {
 	// CountNodes counts the number of nodes in the tree
+	if tree == nil {
+		return 0
+	}
 	tree.lock()
 	defer tree.unlock()
-	return tree.countNodesRecursive(tree.root)
+	return tree.countNodesRecursive(tree.root.Load())
 }
 
 func (tree *FineGrainedSyncTree[T, K]) countNodesRecursive(node *Node[T, K]) int {
 	if node == nil {
 		return 0
 	}
-	return 1 + tree.countNodesRecursive(node.left) + tree.countNodesRecursive(node.right)
+	return 1 + tree.countNodesRecursive(node.left.Load()) + tree.countNodesRecursive(node.right.Load())
 }
 
 func (tree *FineGrainedSyncTree[T, K]) IsValid() bool {
 	// IsValid checks if the tree is a valid binary search tree
+	if tree == nil {
+		return true
+	}
 	tree.lock()
 	defer tree.unlock()
-	return tree.isValidBSTRecursive(tree.root, nil, nil)
+	_, validSizes := tree.isValidSizeRecursive(tree.root.Load())
+	return tree.isValidBSTRecursive(tree.root.Load(), nil, nil) && validSizes
 }
 
 func (tree *FineGrainedSyncTree[T, K]) isValidBSTRecursive(node *Node[T, K], min *K, max *K) bool {
 	if node == nil {
 		return true
 	}
-	if (min != nil && cmp.Compare(node.key, *min) <= 0) || (max != nil && cmp.Compare(node.key, *max) >= 0) {
+	key := node.key()
+	if (min != nil && cmp.Compare(key, *min) <= 0) || (max != nil && cmp.Compare(key, *max) >= 0) {
 		return false
 	}
-	return tree.isValidBSTRecursive(node.left, min, &node.key) && tree.isValidBSTRecursive(node.right, &node.key, max)
+	return tree.isValidBSTRecursive(node.left.Load(), min, &key) && tree.isValidBSTRecursive(node.right.Load(), &key, max)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) isValidSizeRecursive(node *Node[T, K]) (int, bool) {
+	// Returns the real size of the subtree and whether all the stored sizes match
+	if node == nil {
+		return 0, true
+	}
+	left, validLeft := tree.isValidSizeRecursive(node.left.Load())
+	right, validRight := tree.isValidSizeRecursive(node.right.Load())
+	return 1 + left + right, validLeft && validRight && node.sizeOf() == 1+left+right
+}
+
+// Iteration. A cursor keeps the ancestors whose keys are still ahead of it, the nearest one on top,
+// and moves between them with the same hand-over-hand locking as findWithParent. No lock is held
+// between the steps, so the loop body may update the tree. The guarantees:
+//   - keys are yielded in strictly increasing order, each pair was present in the tree at some moment;
+//   - keys present during the whole iteration are yielded exactly once;
+//   - keys inserted or removed concurrently may or may not be yielded.
+// An unlinked node keeps its children, so the cursor reaches the remaining keys through it. Only
+// a two-children Remove carries a key up, possibly behind the cursor, then the cursor seeks again
+// from the root. Without such moves All over n keys takes O(n + h) lock acquisitions.
+
+func (tree *FineGrainedSyncTree[T, K]) Range(lo, hi K) iter.Seq2[K, T] {
+	if tree == nil {
+		return trees.Empty[T, K]()
+	}
+	return trees.Ascend(tree.seek, &lo, &hi)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) All() iter.Seq2[K, T] {
+	if tree == nil {
+		return trees.Empty[T, K]()
+	}
+	return trees.Ascend(tree.seek, nil, nil)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) Keys() iter.Seq[K] {
+	return trees.KeysOf(tree.All())
+}
+
+func (tree *FineGrainedSyncTree[T, K]) Values() iter.Seq[T] {
+	return trees.ValuesOf(tree.All())
+}
+
+func (tree *FineGrainedSyncTree[T, K]) seek(bound *K, inclusive bool) trees.Step[T, K] {
+	shared := tree.readCoupling
+	epoch := tree.relocations.Wait()
+	ancestors := []*Node[T, K]{}
+
+	descend := func(current *Node[T, K]) {
+		// Takes the locked current node and pushes the path down to the smallest key beyond bound
+		for current != nil {
+			next := current.right.Load()
+			if trees.Beyond(current.key(), bound, inclusive, true) {
+				ancestors = append(ancestors, current)
+				next = current.left.Load()
+			}
+			if next != nil {
+				next.acquire(shared)
+			}
+			current.release(shared)
+			current = next
+		}
+	}
+
+	tree.acquire(shared)
+	root := tree.root.Load()
+	if root != nil {
+		root.acquire(shared)
+	}
+	tree.release(shared)
+	descend(root)
+
+	return func() (K, T, bool, bool) {
+		for len(ancestors) > 0 {
+			node := ancestors[len(ancestors)-1]
+			ancestors = ancestors[:len(ancestors)-1]
+
+			node.acquire(shared)
+			pair, removed := node.pair.Load(), node.removed.Load()
+			next := node.right.Load()
+			if next != nil {
+				next.acquire(shared)
+			}
+			node.release(shared)
+			descend(next)
+
+			if !tree.relocations.Unchanged(epoch) {
+				return *(new(K)), *(new(T)), false, true
+			}
+			if !removed {
+				return pair.key, pair.value, true, false
+			}
+		}
+		return *(new(K)), *(new(T)), false, !tree.relocations.Unchanged(epoch)
+	}
+}
+
+// Ordered queries. They search with the same hand-over-hand locking as findWithParent
+// and start over if a successor was moved meanwhile.
+
+func (tree *FineGrainedSyncTree[T, K]) Min() (K, T, bool) {
+	return tree.nearest(nil, true, true)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) Max() (K, T, bool) {
+	return tree.nearest(nil, true, false)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) Floor(key K) (K, T, bool) {
+	return tree.nearest(&key, true, false)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) Ceiling(key K) (K, T, bool) {
+	return tree.nearest(&key, true, true)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) Predecessor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, false)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) Successor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, true)
+}
+
+func (tree *FineGrainedSyncTree[T, K]) nearest(bound *K, inclusive, ascending bool) (K, T, bool) {
+	// The closest key beyond bound in the given direction
+	if tree == nil {
+		return *(new(K)), *(new(T)), false
+	}
+	shared := tree.readCoupling
+	for {
+		epoch := tree.relocations.Wait()
+
+		tree.acquire(shared)
+		current := tree.root.Load()
+		if current != nil {
+			current.acquire(shared)
+		}
+		tree.release(shared)
+
+		var key K
+		var value T
+		found := false
+		for current != nil {
+			next := current.far(ascending)
+			if trees.Beyond(current.key(), bound, inclusive, ascending) {
+				// The current key fits, but a closer one may be on the near side
+				key, value, found = current.key(), current.value(), true
+				next = current.near(ascending)
+			}
+			if next != nil {
+				next.acquire(shared)
+			}
+			current.release(shared)
+			current = next
+		}
+
+		if tree.relocations.Unchanged(epoch) {
+			return key, value, found
+		}
+	}
+}
+
+func (node *Node[T, K]) near(ascending bool) *Node[T, K] {
+	if ascending {
+		return node.left.Load()
+	}
+	return node.right.Load()
+}
+
+func (node *Node[T, K]) far(ascending bool) *Node[T, K] {
+	if ascending {
+		return node.right.Load()
+	}
+	return node.left.Load()
+}
+
+// Order statistics. Insert and Remove update the sizes of the recorded ancestors after the change
+// is made, so the sizes are exact once the concurrent updates are finished. While they run, Rank and
+// Select may be off by the amount of the concurrent insertions and removals.
+
+func (tree *FineGrainedSyncTree[T, K]) Rank(key K) int {
+	if tree == nil {
+		return 0
+	}
+	shared := tree.readCoupling
+	for {
+		epoch := tree.relocations.Wait()
+
+		tree.acquire(shared)
+		current := tree.root.Load()
+		if current != nil {
+			current.acquire(shared)
+		}
+		tree.release(shared)
+
+		rank := 0
+		for current != nil {
+			next := current.left.Load()
+			if cmp.Less(current.key(), key) {
+				// The current node and its left subtree are less than the key
+				rank += 1 + current.left.Load().sizeOf()
+				next = current.right.Load()
+			}
+			if next != nil {
+				next.acquire(shared)
+			}
+			current.release(shared)
+			current = next
+		}
+
+		if tree.relocations.Unchanged(epoch) {
+			return rank
+		}
+	}
+}
+
+func (tree *FineGrainedSyncTree[T, K]) Select(rank int) (K, T, bool) {
+	if tree == nil {
+		return *(new(K)), *(new(T)), false
+	}
+	shared := tree.readCoupling
+	for {
+		epoch := tree.relocations.Wait()
+
+		tree.acquire(shared)
+		current := tree.root.Load()
+		if rank < 0 || rank >= current.sizeOf() {
+			tree.release(shared)
+			return *(new(K)), *(new(T)), false
+		}
+		current.acquire(shared)
+		tree.release(shared)
+
+		remaining := rank
+		for current != nil {
+			left := current.left.Load().sizeOf()
+			if remaining == left {
+				key, value := current.key(), current.value()
+				current.release(shared)
+				if tree.relocations.Unchanged(epoch) {
+					return key, value, true
+				}
+				break
+			}
+			next := current.left.Load()
+			if remaining > left {
+				remaining -= left + 1
+				next = current.right.Load()
+			}
+			if next != nil {
+				next.acquire(shared)
+			}
+			current.release(shared)
+			current = next
+		}
+		// The sizes were changed during the search
+	}
+}
+
+func grow[T any, K cmp.Ordered](path []*Node[T, K], delta int64) {
+	for _, node := range path {
+		node.size.Add(delta)
+	}
+}
+
+func (node *Node[T, K]) sizeOf() int {
+	if node == nil {
+		return 0
+	}
+	return int(node.size.Load())
 }
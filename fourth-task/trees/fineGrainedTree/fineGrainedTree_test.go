@@ -0,0 +1,26 @@
+package fineGrainedTree
+
+import "testing"
+
+// The unlinked successor is unreachable, so a lock left on it cannot be noticed from outside the package.
+
+func TestRemoveUnlocksSuccessor(t *testing.T) {
+	for _, keys := range [][]int{{2, 1, 3}, {2, 1, 4, 3}} {
+		// The successor of the root is its right child or the leftmost node of the right subtree
+		tree := FreshFineGrainedSyncTree[int, int]()
+		for _, key := range keys {
+			tree.Insert(key, key)
+		}
+		successor := tree.root.Load().right.Load()
+		for successor.left.Load() != nil {
+			successor = successor.left.Load()
+		}
+
+		if !tree.Remove(2) {
+			t.Fatalf("Failed to remove a node that was previously added.")
+		}
+		if !successor.mutex.TryLock() {
+			t.Errorf("Error: the successor of the removed node %v stays locked.", keys)
+		}
+	}
+}
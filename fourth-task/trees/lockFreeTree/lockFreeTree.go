@@ -0,0 +1,235 @@
+package lockFreeTree
+
+import (
+	"cmp"
+	"sync/atomic"
+)
+
+// Non-blocking external binary search tree after Ellen, Fatourou, Ruppert and van Breugel,
+// "Non-blocking Binary Search Trees". The keys are stored in the leaves, the internal nodes only
+// route the searches, so a removal unlinks a leaf together with its parent. An update first flags
+// the internal node whose child edge it is going to swap, with a CAS on the update field of the node,
+// and publishes the description of the whole operation there. Any thread that runs into a flag helps
+// to finish that operation instead of waiting, so no operation ever blocks the others.
+
+const (
+	clean = iota
+	insertFlag
+	removeFlag
+	mark // The internal node is being removed, its update field will not change anymore.
+)
+
+// Sentinel keys, which are greater than any real key.
+const (
+	infinity1 = 1
+	infinity2 = 2
+)
+
+type LockFreeSyncTree[T any, K cmp.Ordered] struct {
+	root *Node[T, K] // An internal node with the key infinity2 and two sentinel leaves.
+}
+
+type Node[T any, K cmp.Ordered] struct {
+	key      K
+	infinity int // Zero for real keys, otherwise the sentinel key of the node.
+	value    T   // Only leaves have values.
+	leaf     bool
+	left     atomic.Pointer[Node[T, K]]
+	right    atomic.Pointer[Node[T, K]]
+	update   atomic.Pointer[update[T, K]] // Every change of the state allocates a new update.
+}
+
+type update[T any, K cmp.Ordered] struct {
+	state int
+	info  *info[T, K]
+}
+
+type info[T any, K cmp.Ordered] struct {
+	// The insertion replaces the leaf l, which is a child of p, with replacement,
+	// the removal replaces p, which is a child of gp, with the sibling of l.
+	grandparent *Node[T, K]
+	parent      *Node[T, K]
+	leaf        *Node[T, K]
+	replacement *Node[T, K]
+	pupdate     *update[T, K] // The update of the parent seen by the removal.
+	flag        *update[T, K] // The flag published by the operation.
+}
+
+func FreshLockFreeSyncTree[T any, K cmp.Ordered]() *LockFreeSyncTree[T, K] {
+	root := freshInternal[T, K](*new(K), infinity2)
+	root.left.Store(&Node[T, K]{infinity: infinity1, leaf: true})
+	root.right.Store(&Node[T, K]{infinity: infinity2, leaf: true})
+	return &LockFreeSyncTree[T, K]{root: root}
+}
+
+func freshInternal[T any, K cmp.Ordered](key K, infinity int) *Node[T, K] {
+	node := &Node[T, K]{key: key, infinity: infinity}
+	node.update.Store(&update[T, K]{state: clean})
+	return node
+}
+
+func (node *Node[T, K]) above(key K) bool {
+	// Whether the key is less than the key of the node
+	return node.infinity != 0 || cmp.Less(key, node.key)
+}
+
+func (node *Node[T, K]) less(other *Node[T, K]) bool {
+	// Whether the key of the node is less than the key of the other node
+	if node.infinity != 0 || other.infinity != 0 {
+		return node.infinity < other.infinity
+	}
+	return cmp.Less(node.key, other.key)
+}
+
+func (node *Node[T, K]) holds(key K) bool {
+	return node.infinity == 0 && node.key == key
+}
+
+func (tree *LockFreeSyncTree[T, K]) search(key K) (*Node[T, K], *Node[T, K], *Node[T, K], *update[T, K], *update[T, K]) {
+	// Returns the leaf on the path of the key, its parent and grandparent, and the updates of the latter two
+	var grandparent, parent *Node[T, K]
+	var pupdate, gpupdate *update[T, K]
+	current := tree.root
+	for !current.leaf {
+		grandparent, parent = parent, current
+		gpupdate, pupdate = pupdate, current.update.Load()
+		if current.above(key) {
+			current = current.left.Load()
+		} else {
+			current = current.right.Load()
+		}
+	}
+	return grandparent, parent, current, pupdate, gpupdate
+}
+
+func (tree *LockFreeSyncTree[T, K]) Find(key K) (T, bool) {
+	if tree == nil {
+		return *(new(T)), false
+	}
+	_, _, leaf, _, _ := tree.search(key)
+	if !leaf.holds(key) {
+		return *(new(T)), false
+	}
+	return leaf.value, true
+}
+
+func (tree *LockFreeSyncTree[T, K]) Insert(key K, value T) {
+	if tree == nil {
+		return
+	}
+	newLeaf := &Node[T, K]{key: key, value: value, leaf: true}
+	for {
+		_, parent, leaf, pupdate, _ := tree.search(key)
+		if pupdate.state != clean {
+			tree.help(pupdate)
+			continue
+		}
+
+		// An existing key gets a new leaf, otherwise the leaf is replaced
+		// with a new internal node over the new leaf and a copy of the old one
+		replacement := newLeaf
+		if !leaf.holds(key) {
+			sibling := &Node[T, K]{key: leaf.key, infinity: leaf.infinity, value: leaf.value, leaf: true}
+			if newLeaf.less(sibling) {
+				replacement = freshInternal[T, K](sibling.key, sibling.infinity)
+				replacement.left.Store(newLeaf)
+				replacement.right.Store(sibling)
+			} else {
+				replacement = freshInternal[T, K](key, 0)
+				replacement.left.Store(sibling)
+				replacement.right.Store(newLeaf)
+			}
+		}
+
+		operation := &info[T, K]{parent: parent, leaf: leaf, replacement: replacement}
+		operation.flag = &update[T, K]{state: insertFlag, info: operation}
+		if parent.update.CompareAndSwap(pupdate, operation.flag) {
+			tree.helpInsert(operation)
+			return
+		}
+		tree.help(parent.update.Load())
+	}
+}
+
+func (tree *LockFreeSyncTree[T, K]) Remove(key K) bool {
+	if tree == nil {
+		return false
+	}
+	for {
+		grandparent, parent, leaf, pupdate, gpupdate := tree.search(key)
+		if !leaf.holds(key) {
+			return false
+		}
+		if gpupdate.state != clean {
+			tree.help(gpupdate)
+			continue
+		}
+		if pupdate.state != clean {
+			tree.help(pupdate)
+			continue
+		}
+
+		operation := &info[T, K]{grandparent: grandparent, parent: parent, leaf: leaf, pupdate: pupdate}
+		operation.flag = &update[T, K]{state: removeFlag, info: operation}
+		if grandparent.update.CompareAndSwap(gpupdate, operation.flag) {
+			if tree.helpRemove(operation) {
+				return true
+			}
+		} else {
+			tree.help(grandparent.update.Load())
+		}
+	}
+}
+
+func (tree *LockFreeSyncTree[T, K]) help(current *update[T, K]) {
+	// Finishes the operation that has flagged or marked a node
+	switch current.state {
+	case insertFlag:
+		tree.helpInsert(current.info)
+	case removeFlag:
+		tree.helpRemove(current.info)
+	case mark:
+		tree.helpMarked(current.info)
+	}
+}
+
+func (tree *LockFreeSyncTree[T, K]) helpInsert(operation *info[T, K]) {
+	tree.casChild(operation.parent, operation.leaf, operation.replacement)
+	operation.parent.update.CompareAndSwap(operation.flag, &update[T, K]{state: clean, info: operation})
+}
+
+func (tree *LockFreeSyncTree[T, K]) helpRemove(operation *info[T, K]) bool {
+	// Marks the parent, or backtracks if another operation has flagged it first
+	marked := &update[T, K]{state: mark, info: operation}
+	if operation.parent.update.CompareAndSwap(operation.pupdate, marked) {
+		tree.helpMarked(operation)
+		return true
+	}
+	current := operation.parent.update.Load()
+	if current.state == mark && current.info == operation {
+		// Another thread has marked it for this removal
+		tree.helpMarked(operation)
+		return true
+	}
+	tree.help(current)
+	operation.grandparent.update.CompareAndSwap(operation.flag, &update[T, K]{state: clean, info: operation})
+	return false
+}
+
+func (tree *LockFreeSyncTree[T, K]) helpMarked(operation *info[T, K]) {
+	// Replaces the marked parent with the sibling of the removed leaf
+	sibling := operation.parent.left.Load()
+	if sibling == operation.leaf {
+		sibling = operation.parent.right.Load()
+	}
+	tree.casChild(operation.grandparent, operation.parent, sibling)
+	operation.grandparent.update.CompareAndSwap(operation.flag, &update[T, K]{state: clean, info: operation})
+}
+
+func (tree *LockFreeSyncTree[T, K]) casChild(parent, old, new *Node[T, K]) {
+	if new.less(parent) {
+		parent.left.CompareAndSwap(old, new)
+	} else {
+		parent.right.CompareAndSwap(old, new)
+	}
+}
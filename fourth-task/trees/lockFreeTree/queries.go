@@ -0,0 +1,231 @@
+package lockFreeTree
+
+import (
+	"bst/trees"
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// Ordered queries walk the tree in order from the root without helping, skipping the subtrees
+// that cannot hold a fitting key. A removed internal node keeps its children forever, so a walk
+// that went through it still ends up in leaves that were in the tree at some moment.
+
+func (tree *LockFreeSyncTree[T, K]) Min() (K, T, bool) {
+	return tree.nearest(nil, true, true)
+}
+
+func (tree *LockFreeSyncTree[T, K]) Max() (K, T, bool) {
+	return tree.nearest(nil, true, false)
+}
+
+func (tree *LockFreeSyncTree[T, K]) Floor(key K) (K, T, bool) {
+	return tree.nearest(&key, true, false)
+}
+
+func (tree *LockFreeSyncTree[T, K]) Ceiling(key K) (K, T, bool) {
+	return tree.nearest(&key, true, true)
+}
+
+func (tree *LockFreeSyncTree[T, K]) Predecessor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, false)
+}
+
+func (tree *LockFreeSyncTree[T, K]) Successor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, true)
+}
+
+func (tree *LockFreeSyncTree[T, K]) nearest(bound *K, inclusive, ascending bool) (K, T, bool) {
+	// The closest key beyond bound in the given direction
+	var key K
+	var value T
+	if tree == nil {
+		return key, value, false
+	}
+	leaf := tree.nearestRecursive(tree.root, bound, inclusive, ascending)
+	if leaf == nil {
+		return key, value, false
+	}
+	return leaf.key, leaf.value, true
+}
+
+func (tree *LockFreeSyncTree[T, K]) nearestRecursive(node *Node[T, K], bound *K, inclusive, ascending bool) *Node[T, K] {
+	if node.leaf {
+		if node.infinity != 0 || !trees.Beyond(node.key, bound, inclusive, ascending) {
+			return nil
+		}
+		return node
+	}
+	if node.reaches(bound, inclusive, ascending) {
+		if found := tree.nearestRecursive(node.near(ascending), bound, inclusive, ascending); found != nil {
+			return found
+		}
+	}
+	return tree.nearestRecursive(node.far(ascending), bound, inclusive, ascending)
+}
+
+func (node *Node[T, K]) reaches(bound *K, inclusive, ascending bool) bool {
+	// Whether the near subtree of the internal node may hold keys beyond bound,
+	// the left subtree holds the keys less than the key of the node and the right one the rest
+	if bound == nil {
+		return true
+	}
+	if node.infinity != 0 {
+		return ascending
+	}
+	if ascending {
+		return cmp.Less(*bound, node.key)
+	}
+	return trees.Beyond(node.key, bound, inclusive, false)
+}
+
+func (node *Node[T, K]) near(ascending bool) *Node[T, K] {
+	if ascending {
+		return node.left.Load()
+	}
+	return node.right.Load()
+}
+
+func (node *Node[T, K]) far(ascending bool) *Node[T, K] {
+	if ascending {
+		return node.right.Load()
+	}
+	return node.left.Load()
+}
+
+// Iteration. A cursor keeps the internal nodes whose right subtrees are still ahead of it and walks
+// the leaves in order without helping, so keys that are inserted or removed concurrently may or may
+// not be yielded, but the keys are strictly increasing and each yielded pair was present at some moment.
+// The walk is never lost: the keys stay in the leaves and a removed internal node keeps its children.
+// A full walk visits every node once, O(n) in total.
+
+func (tree *LockFreeSyncTree[T, K]) Range(lo, hi K) iter.Seq2[K, T] {
+	if tree == nil {
+		return trees.Empty[T, K]()
+	}
+	return trees.Ascend(tree.seek, &lo, &hi)
+}
+
+func (tree *LockFreeSyncTree[T, K]) All() iter.Seq2[K, T] {
+	if tree == nil {
+		return trees.Empty[T, K]()
+	}
+	return trees.Ascend(tree.seek, nil, nil)
+}
+
+func (tree *LockFreeSyncTree[T, K]) Keys() iter.Seq[K] {
+	return trees.KeysOf(tree.All())
+}
+
+func (tree *LockFreeSyncTree[T, K]) Values() iter.Seq[T] {
+	return trees.ValuesOf(tree.All())
+}
+
+func (tree *LockFreeSyncTree[T, K]) seek(bound *K, inclusive bool) trees.Step[T, K] {
+	ancestors := []*Node[T, K]{}
+
+	descend := func(node *Node[T, K]) *Node[T, K] {
+		// Pushes the path down to the leaf, skipping the left subtrees without keys beyond bound
+		for !node.leaf {
+			if node.reaches(bound, inclusive, true) {
+				ancestors = append(ancestors, node)
+				node = node.left.Load()
+			} else {
+				node = node.right.Load()
+			}
+		}
+		return node
+	}
+	leaf := descend(tree.root)
+
+	return func() (K, T, bool, bool) {
+		// The sentinels are greater than any real key, so nothing follows them
+		for leaf != nil && leaf.infinity == 0 {
+			current := leaf
+			leaf = nil
+			if len(ancestors) > 0 {
+				node := ancestors[len(ancestors)-1]
+				ancestors = ancestors[:len(ancestors)-1]
+				leaf = descend(node.right.Load())
+			}
+			if trees.Beyond(current.key, bound, inclusive, true) {
+				return current.key, current.value, true, false
+			}
+		}
+		return *(new(K)), *(new(T)), false, false
+	}
+}
+
+// The following methods read the tree without helping,
+// their results are exact only when no updates run concurrently.
+
+func (tree *LockFreeSyncTree[T, K]) Print() {
+	if tree == nil {
+		return
+	}
+	tree.printHelper(tree.root, 0)
+}
+
+func (tree *LockFreeSyncTree[T, K]) printHelper(node *Node[T, K], indent int) {
+	if node == nil || (node.leaf && node.infinity != 0) {
+		return
+	}
+
+	// Print the right subtree with indentation
+	tree.printHelper(node.right.Load(), indent+4)
+
+	// Print current node, internal nodes are in brackets
+	fmt.Print(strings.Repeat(" ", indent))
+	if node.infinity != 0 {
+		fmt.Printf("(∞%d)\n", node.infinity)
+	} else if node.leaf {
+		fmt.Printf("%v\n", node.key)
+	} else {
+		fmt.Printf("(%v)\n", node.key)
+	}
+
+	// Print left subtree with indentation
+	tree.printHelper(node.left.Load(), indent+4)
+}
+
+func (tree *LockFreeSyncTree[T, K]) CountNodes() int {
+	// CountNodes counts the number of leaves with real keys
+	if tree == nil {
+		return 0
+	}
+	return tree.countNodesRecursive(tree.root)
+}
+
+func (tree *LockFreeSyncTree[T, K]) countNodesRecursive(node *Node[T, K]) int {
+	if node.leaf {
+		if node.infinity != 0 {
+			return 0
+		}
+		return 1
+	}
+	return tree.countNodesRecursive(node.left.Load()) + tree.countNodesRecursive(node.right.Load())
+}
+
+func (tree *LockFreeSyncTree[T, K]) IsValid() bool {
+	// IsValid checks if the leaves are ordered by the internal nodes,
+	// which have two children and no unfinished operations
+	if tree == nil {
+		return true
+	}
+	return tree.isValidRecursive(tree.root, nil, nil)
+}
+
+func (tree *LockFreeSyncTree[T, K]) isValidRecursive(node *Node[T, K], lower *Node[T, K], upper *Node[T, K]) bool {
+	// The keys of the subtree must be at least lower and less than upper
+	if node == nil || (lower != nil && node.less(lower)) || (upper != nil && !node.less(upper)) {
+		return false
+	}
+	if node.leaf {
+		return true
+	}
+	if node.update.Load().state != clean {
+		return false
+	}
+	return tree.isValidRecursive(node.left.Load(), lower, node) && tree.isValidRecursive(node.right.Load(), node, upper)
+}
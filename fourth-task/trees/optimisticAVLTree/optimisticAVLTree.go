@@ -0,0 +1,335 @@
+package optimisticAVLTree
+
+import (
+	"cmp"
+	"sync"
+	"sync/atomic"
+)
+
+// Concurrent AVL tree after Bronson, Casper, Chafi and Olukotun, "A Practical Concurrent Binary Search Tree".
+// Readers take no locks. Every node has a version that changes when the node is rotated down or unlinked,
+// so a reader that moved from a node to its child validates the version of the node and repeats the step
+// if the node has shrunk meanwhile. Writers lock only the nodes they change. A removed node with two
+// children stays in the tree as a routing node without a value, and the writers restore the balance
+// on their way up, so the heights are relaxed only while the updates run.
+
+const (
+	unlinked        = 1 // The node was removed from the tree.
+	shrinking       = 2 // The node is being rotated down.
+	shrinkCountIncr = 4 // Every finished rotation of the node adds it to the version.
+)
+
+const spinsAmount = 100 // How many times a reader checks a shrinking node before blocking on its mutex.
+
+type outcome int
+
+const (
+	done outcome = iota
+	absent
+	retry
+)
+
+type OptimisticAVLSyncTree[T any, K cmp.Ordered] struct {
+	holder *Node[T, K] // The root is the right child of the holder, which is never rotated.
+}
+
+type Node[T any, K cmp.Ordered] struct {
+	key     K
+	value   atomic.Pointer[T] // Nil for routing nodes.
+	left    atomic.Pointer[Node[T, K]]
+	right   atomic.Pointer[Node[T, K]]
+	parent  atomic.Pointer[Node[T, K]]
+	height  atomic.Int64 // The amount of nodes on the longest path down from the node.
+	version atomic.Uint64
+	mutex   sync.Mutex
+}
+
+func FreshOptimisticAVLSyncTree[T any, K cmp.Ordered]() *OptimisticAVLSyncTree[T, K] {
+	return &OptimisticAVLSyncTree[T, K]{
+		holder: &Node[T, K]{},
+	}
+}
+
+func freshNode[T any, K cmp.Ordered](key K, value *T, parent *Node[T, K]) *Node[T, K] {
+	node := &Node[T, K]{key: key}
+	node.value.Store(value)
+	node.parent.Store(parent)
+	node.height.Store(1)
+	return node
+}
+
+func (node *Node[T, K]) lock() {
+	node.mutex.Lock()
+}
+
+func (node *Node[T, K]) unlock() {
+	node.mutex.Unlock()
+}
+
+func (node *Node[T, K]) child(dir int) *Node[T, K] {
+	// The left child for a negative direction, the right one otherwise
+	if dir < 0 {
+		return node.left.Load()
+	}
+	return node.right.Load()
+}
+
+func (node *Node[T, K]) setChild(dir int, child *Node[T, K]) {
+	if dir < 0 {
+		node.left.Store(child)
+	} else {
+		node.right.Store(child)
+	}
+}
+
+func (node *Node[T, K]) heightOf() int64 {
+	if node == nil {
+		return 0
+	}
+	return node.height.Load()
+}
+
+func (node *Node[T, K]) waitUntilNotChanging() {
+	version := node.version.Load()
+	if version&shrinking == 0 {
+		return
+	}
+	for i := 0; i < spinsAmount; i++ {
+		if node.version.Load() != version {
+			return
+		}
+	}
+	// The rotation holds the mutex of the node
+	node.lock()
+	node.unlock()
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Find(key K) (T, bool) {
+	if tree == nil {
+		return *(new(T)), false
+	}
+	for {
+		value, result := tree.attemptGet(key, tree.holder, 1, 0)
+		if result == done {
+			return *value, true
+		}
+		if result == absent {
+			return *(new(T)), false
+		}
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) attemptGet(key K, node *Node[T, K], dir int, nodeVersion uint64) (*T, outcome) {
+	// Searches the key in the child of the node, which had the given version
+	for {
+		child := node.child(dir)
+		if node.version.Load() != nodeVersion {
+			return nil, retry
+		}
+		if child == nil {
+			return nil, absent
+		}
+
+		comp := cmp.Compare(key, child.key)
+		if comp == 0 {
+			// Unlinked nodes and routing nodes have no value
+			value := child.value.Load()
+			if value == nil {
+				return nil, absent
+			}
+			return value, done
+		}
+
+		childVersion := child.version.Load()
+		if childVersion&(shrinking|unlinked) != 0 {
+			child.waitUntilNotChanging()
+		} else if child == node.child(dir) {
+			if node.version.Load() != nodeVersion {
+				return nil, retry
+			}
+			value, result := tree.attemptGet(key, child, comp, childVersion)
+			if result != retry {
+				return value, result
+			}
+		}
+		// The child has changed, read it again
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Insert(key K, value T) {
+	if tree == nil {
+		return
+	}
+	for tree.attemptPut(key, &value, tree.holder, 1, 0) == retry {
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) attemptPut(key K, value *T, node *Node[T, K], dir int, nodeVersion uint64) outcome {
+	for {
+		child := node.child(dir)
+		if node.version.Load() != nodeVersion {
+			return retry
+		}
+
+		if child == nil {
+			// Insert a new leaf, unless another one has taken the place
+			node.lock()
+			if node.version.Load() != nodeVersion {
+				node.unlock()
+				return retry
+			}
+			if node.child(dir) != nil {
+				node.unlock()
+				continue
+			}
+			node.setChild(dir, freshNode(key, value, node))
+			node.unlock()
+
+			tree.fixHeightAndRebalance(node)
+			return done
+		}
+
+		comp := cmp.Compare(key, child.key)
+		if comp == 0 {
+			// Update the value, a routing node gets its value back
+			child.lock()
+			if child.version.Load()&unlinked != 0 {
+				child.unlock()
+				continue
+			}
+			child.value.Store(value)
+			child.unlock()
+			return done
+		}
+
+		childVersion := child.version.Load()
+		if childVersion&(shrinking|unlinked) != 0 {
+			child.waitUntilNotChanging()
+		} else if child == node.child(dir) {
+			if node.version.Load() != nodeVersion {
+				return retry
+			}
+			if tree.attemptPut(key, value, child, comp, childVersion) != retry {
+				return done
+			}
+		}
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Remove(key K) bool {
+	if tree == nil {
+		return false
+	}
+	for {
+		result := tree.attemptRemove(key, tree.holder, 1, 0)
+		if result != retry {
+			return result == done
+		}
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) attemptRemove(key K, node *Node[T, K], dir int, nodeVersion uint64) outcome {
+	for {
+		child := node.child(dir)
+		if node.version.Load() != nodeVersion {
+			return retry
+		}
+		if child == nil {
+			return absent
+		}
+
+		comp := cmp.Compare(key, child.key)
+		if comp == 0 {
+			result := tree.attemptRemoveNode(node, child)
+			if result != retry {
+				return result
+			}
+			continue
+		}
+
+		childVersion := child.version.Load()
+		if childVersion&(shrinking|unlinked) != 0 {
+			child.waitUntilNotChanging()
+		} else if child == node.child(dir) {
+			if node.version.Load() != nodeVersion {
+				return retry
+			}
+			result := tree.attemptRemove(key, child, comp, childVersion)
+			if result != retry {
+				return result
+			}
+		}
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) attemptRemoveNode(parent *Node[T, K], node *Node[T, K]) outcome {
+	if node.value.Load() == nil {
+		return absent
+	}
+
+	// A node with two children becomes a routing node
+	if node.left.Load() != nil && node.right.Load() != nil {
+		node.lock()
+		if node.version.Load()&unlinked != 0 || node.left.Load() == nil || node.right.Load() == nil {
+			node.unlock()
+			return retry
+		}
+		removed := node.value.Swap(nil)
+		node.unlock()
+		if removed == nil {
+			return absent
+		}
+		return done
+	}
+
+	// Other nodes are unlinked
+	parent.lock()
+	if parent.version.Load()&unlinked != 0 || node.parent.Load() != parent {
+		parent.unlock()
+		return retry
+	}
+	node.lock()
+	if node.value.Load() == nil {
+		node.unlock()
+		parent.unlock()
+		return absent
+	}
+	if !tree.attemptUnlink(parent, node) {
+		// The node has got the second child meanwhile
+		node.value.Store(nil)
+	}
+	node.unlock()
+	parent.unlock()
+
+	tree.fixHeightAndRebalance(parent)
+	return done
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) attemptUnlink(parent *Node[T, K], node *Node[T, K]) bool {
+	// Both nodes are locked, the node must have at most one child
+	parentLeft := parent.left.Load()
+	if parentLeft != node && parent.right.Load() != node {
+		return false
+	}
+	left, right := node.left.Load(), node.right.Load()
+	if left != nil && right != nil {
+		return false
+	}
+
+	splice := left
+	if splice == nil {
+		splice = right
+	}
+	if parentLeft == node {
+		parent.left.Store(splice)
+	} else {
+		parent.right.Store(splice)
+	}
+	if splice != nil {
+		splice.parent.Store(parent)
+	}
+
+	node.version.Store(unlinked)
+	node.value.Store(nil)
+	return true
+}
@@ -0,0 +1,280 @@
+package optimisticAVLTree
+
+import (
+	"bst/trees"
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// Ordered queries search with the same validation as Find, a routing node is passed by
+// and the closest key is looked for in its far subtree, if its near subtree has none.
+
+func (tree *OptimisticAVLSyncTree[T, K]) Min() (K, T, bool) {
+	return tree.nearest(nil, true, true)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Max() (K, T, bool) {
+	return tree.nearest(nil, true, false)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Floor(key K) (K, T, bool) {
+	return tree.nearest(&key, true, false)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Ceiling(key K) (K, T, bool) {
+	return tree.nearest(&key, true, true)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Predecessor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, false)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Successor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, true)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) nearest(bound *K, inclusive, ascending bool) (K, T, bool) {
+	// The closest key beyond bound in the given direction
+	var key K
+	var value T
+	if tree == nil {
+		return key, value, false
+	}
+	for {
+		node, nodeValue, result := tree.attemptNearest(tree.holder, 1, 0, bound, inclusive, ascending)
+		if result == done {
+			return node.key, *nodeValue, true
+		}
+		if result == absent {
+			return key, value, false
+		}
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) attemptNearest(node *Node[T, K], dir int, nodeVersion uint64, bound *K, inclusive, ascending bool) (*Node[T, K], *T, outcome) {
+	near, far := 1, -1
+	if ascending {
+		near, far = -1, 1
+	}
+	for {
+		child := node.child(dir)
+		if node.version.Load() != nodeVersion {
+			return nil, nil, retry
+		}
+		if child == nil {
+			return nil, nil, absent
+		}
+
+		childVersion := child.version.Load()
+		if childVersion&(shrinking|unlinked) != 0 {
+			child.waitUntilNotChanging()
+			continue
+		}
+		if child != node.child(dir) {
+			continue
+		}
+		if node.version.Load() != nodeVersion {
+			return nil, nil, retry
+		}
+
+		var found *Node[T, K]
+		var value *T
+		var result outcome
+		if trees.Beyond(child.key, bound, inclusive, ascending) {
+			found, value, result = tree.attemptNearest(child, near, childVersion, bound, inclusive, ascending)
+			if result == absent {
+				if value = child.value.Load(); value != nil {
+					found, result = child, done
+				} else {
+					// Every key in the far subtree of the routing node fits
+					found, value, result = tree.attemptNearest(child, far, childVersion, nil, inclusive, ascending)
+				}
+			}
+		} else {
+			found, value, result = tree.attemptNearest(child, far, childVersion, bound, inclusive, ascending)
+		}
+		if result != retry {
+			return found, value, result
+		}
+	}
+}
+
+// Iteration. A cursor keeps the ancestors whose keys are still ahead of it together with their versions,
+// read when the cursor passed them. A node that has not shrunk since then still holds every key of its
+// range, so the cursor goes on into its right subtree, otherwise the cursor seeks again from the holder.
+// Keys that are inserted or removed concurrently may or may not be yielded, but the keys are strictly
+// increasing, each yielded pair was present at some moment and a key present during the whole iteration
+// is yielded exactly once. Without concurrent rotations a full walk reads every node once.
+
+type position[T any, K cmp.Ordered] struct {
+	node    *Node[T, K]
+	version uint64
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Range(lo, hi K) iter.Seq2[K, T] {
+	if tree == nil {
+		return trees.Empty[T, K]()
+	}
+	return trees.Ascend(tree.seek, &lo, &hi)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) All() iter.Seq2[K, T] {
+	if tree == nil {
+		return trees.Empty[T, K]()
+	}
+	return trees.Ascend(tree.seek, nil, nil)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Keys() iter.Seq[K] {
+	return trees.KeysOf(tree.All())
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Values() iter.Seq[T] {
+	return trees.ValuesOf(tree.All())
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) seek(bound *K, inclusive bool) trees.Step[T, K] {
+	ancestors := []position[T, K]{}
+
+	descend := func(node *Node[T, K], nodeVersion uint64) bool {
+		// Pushes the path down from the right child of the node to the smallest key beyond bound,
+		// moving to a child as attemptNearest does. False if a node of the path has changed
+		dir := 1
+		for {
+			child := node.child(dir)
+			if node.version.Load() != nodeVersion {
+				return false
+			}
+			if child == nil {
+				return true
+			}
+
+			childVersion := child.version.Load()
+			if childVersion&(shrinking|unlinked) != 0 {
+				child.waitUntilNotChanging()
+				continue
+			}
+			if child != node.child(dir) {
+				continue
+			}
+			if node.version.Load() != nodeVersion {
+				return false
+			}
+
+			dir = 1
+			if trees.Beyond(child.key, bound, inclusive, true) {
+				ancestors = append(ancestors, position[T, K]{node: child, version: childVersion})
+				dir = -1
+			}
+			node, nodeVersion = child, childVersion
+		}
+	}
+	lost := !descend(tree.holder, 0)
+
+	return func() (K, T, bool, bool) {
+		for !lost && len(ancestors) > 0 {
+			top := ancestors[len(ancestors)-1]
+			ancestors = ancestors[:len(ancestors)-1]
+
+			// Routing nodes have no value, but the keys beyond them are still ahead
+			value := top.node.value.Load()
+			lost = top.node.version.Load() != top.version || !descend(top.node, top.version)
+			if !lost && value != nil {
+				return top.node.key, *value, true, false
+			}
+		}
+		return *(new(K)), *(new(T)), false, lost
+	}
+}
+
+// The following methods read the tree without locks and validation,
+// their results are exact only when no updates run concurrently.
+
+func (tree *OptimisticAVLSyncTree[T, K]) Print() {
+	if tree == nil {
+		return
+	}
+	tree.printHelper(tree.holder.right.Load(), 0)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) printHelper(node *Node[T, K], indent int) {
+	if node == nil {
+		return
+	}
+
+	// Print the right subtree with indentation
+	tree.printHelper(node.right.Load(), indent+4)
+
+	// Print current node, routing nodes are in brackets
+	fmt.Print(strings.Repeat(" ", indent))
+	if node.value.Load() == nil {
+		fmt.Printf("(%v)\n", node.key)
+	} else {
+		fmt.Printf("%v\n", node.key)
+	}
+
+	// Print left subtree with indentation
+	tree.printHelper(node.left.Load(), indent+4)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) CountNodes() int {
+	// CountNodes counts the number of nodes with values in the tree
+	if tree == nil {
+		return 0
+	}
+	return tree.countNodesRecursive(tree.holder.right.Load())
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) countNodesRecursive(node *Node[T, K]) int {
+	if node == nil {
+		return 0
+	}
+	count := tree.countNodesRecursive(node.left.Load()) + tree.countNodesRecursive(node.right.Load())
+	if node.value.Load() != nil {
+		count++
+	}
+	return count
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) Height() int {
+	// Height returns the amount of nodes on the longest path from the root
+	if tree == nil {
+		return 0
+	}
+	return int(tree.holder.right.Load().heightOf())
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) IsValid() bool {
+	// IsValid checks if the tree is a valid binary search tree, whose nodes are linked to
+	// their parents, have correct heights and balance, and routing nodes have two children
+	if tree == nil {
+		return true
+	}
+	_, valid := tree.isValidRecursive(tree.holder, tree.holder.right.Load(), nil, nil)
+	return valid
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) isValidRecursive(parent *Node[T, K], node *Node[T, K], lower *K, upper *K) (int64, bool) {
+	// Returns the real height of the subtree and whether it is valid
+	if node == nil {
+		return 0, true
+	}
+	if (lower != nil && cmp.Compare(node.key, *lower) <= 0) || (upper != nil && cmp.Compare(node.key, *upper) >= 0) {
+		return 0, false
+	}
+	if node.parent.Load() != parent || node.version.Load()&(shrinking|unlinked) != 0 {
+		return 0, false
+	}
+	left, right := node.left.Load(), node.right.Load()
+	if (left == nil || right == nil) && node.value.Load() == nil {
+		return 0, false
+	}
+
+	leftHeight, validLeft := tree.isValidRecursive(node, left, lower, &node.key)
+	rightHeight, validRight := tree.isValidRecursive(node, right, &node.key, upper)
+	height := 1 + max(leftHeight, rightHeight)
+	balanced := leftHeight-rightHeight <= 1 && rightHeight-leftHeight <= 1
+	return height, validLeft && validRight && balanced && node.height.Load() == height
+}
@@ -0,0 +1,390 @@
+package optimisticAVLTree
+
+import "cmp"
+
+// Relaxed rebalancing. After an update the writer walks up to the root, fixing the heights and rotating
+// the unbalanced nodes. A rotation locks the parent, the node and every node that gets a new parent,
+// each function ending with Locked expects the nodes it is given to be locked already. It returns the next
+// node to look at: a node damaged by the rotation itself or the next one on the way up.
+
+const (
+	unlinkRequired    = -1 // A routing node with less than two children.
+	rebalanceRequired = -2 // The heights of the children differ by more than one.
+	nothingRequired   = -3
+)
+
+func (node *Node[T, K]) condition() int64 {
+	// Returns one of the conditions above or the new height of the node
+	if node == nil {
+		return nothingRequired
+	}
+	left, right := node.left.Load(), node.right.Load()
+	if (left == nil || right == nil) && node.value.Load() == nil {
+		return unlinkRequired
+	}
+
+	height := node.height.Load()
+	leftHeight, rightHeight := left.heightOf(), right.heightOf()
+	newHeight := 1 + max(leftHeight, rightHeight)
+	balance := leftHeight - rightHeight
+	if balance < -1 || balance > 1 {
+		return rebalanceRequired
+	}
+	if height != newHeight {
+		return newHeight
+	}
+	return nothingRequired
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) fixHeightAndRebalance(node *Node[T, K]) {
+	// The walk goes on up to the holder, which has no parent: a rotation may return a damaged node
+	// below the ones it changed, so their ancestors must be checked again after the damage is repaired
+	for node != nil && node.parent.Load() != nil {
+		if node.version.Load()&unlinked != 0 {
+			return
+		}
+		condition := node.condition()
+		if condition == nothingRequired {
+			// A routing child left with a single child by a double rotation is unlinked before going up
+			if left := node.left.Load(); left.condition() == unlinkRequired {
+				node = left
+			} else if right := node.right.Load(); right.condition() == unlinkRequired {
+				node = right
+			} else {
+				node = node.parent.Load()
+			}
+			continue
+		}
+
+		if condition != unlinkRequired && condition != rebalanceRequired {
+			node.lock()
+			next := tree.fixHeightLocked(node)
+			node.unlock()
+			node = next
+			continue
+		}
+
+		parent := node.parent.Load()
+		parent.lock()
+		if parent.version.Load()&unlinked == 0 && node.parent.Load() == parent {
+			node.lock()
+			next := tree.rebalanceLocked(parent, node)
+			node.unlock()
+			node = next
+		}
+		parent.unlock()
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) fixHeightLocked(node *Node[T, K]) *Node[T, K] {
+	condition := node.condition()
+	switch condition {
+	case unlinkRequired, rebalanceRequired:
+		return node
+	case nothingRequired:
+		return node.parent.Load()
+	default:
+		node.height.Store(condition)
+		return node.parent.Load()
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) rebalanceLocked(parent *Node[T, K], node *Node[T, K]) *Node[T, K] {
+	left, right := node.left.Load(), node.right.Load()
+	if (left == nil || right == nil) && node.value.Load() == nil {
+		if tree.attemptUnlink(parent, node) {
+			return tree.fixHeightLocked(parent)
+		}
+		return node
+	}
+
+	height := node.height.Load()
+	leftHeight, rightHeight := left.heightOf(), right.heightOf()
+	newHeight := 1 + max(leftHeight, rightHeight)
+	balance := leftHeight - rightHeight
+	if balance > 1 {
+		return tree.rebalanceToRightLocked(parent, node, left, rightHeight)
+	}
+	if balance < -1 {
+		return tree.rebalanceToLeftLocked(parent, node, right, leftHeight)
+	}
+	if newHeight != height {
+		node.height.Store(newHeight)
+		return tree.fixHeightLocked(parent)
+	}
+	return parent
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) rebalanceToRightLocked(parent, node, left *Node[T, K], rightHeight int64) *Node[T, K] {
+	left.lock()
+	defer left.unlock()
+
+	if left.height.Load()-rightHeight <= 1 {
+		// The heights have changed, look at the node again
+		return node
+	}
+	leftRight := left.right.Load()
+	leftLeftHeight := left.left.Load().heightOf()
+	if leftRight == nil {
+		return tree.rotateRightLocked(parent, node, left, rightHeight, leftLeftHeight, nil, 0)
+	}
+
+	// Every node that gets a new parent is locked, so that a writer who has just changed its height
+	// repairs either the old parent before the rotation reads the height or the new parent after it
+	leftRight.lock()
+	leftRightHeight := leftRight.height.Load()
+	if leftLeftHeight >= leftRightHeight {
+		next := tree.rotateRightLocked(parent, node, left, rightHeight, leftLeftHeight, leftRight, leftRightHeight)
+		leftRight.unlock()
+		return next
+	}
+
+	leftRightLeft, leftRightRight := leftRight.left.Load(), leftRight.right.Load()
+	lockAll(leftRightLeft, leftRightRight)
+	defer unlockAll(leftRightLeft, leftRightRight, leftRight)
+	leftRightLeftHeight := leftRightLeft.heightOf()
+	balance := leftLeftHeight - leftRightLeftHeight
+	if balance >= -1 && balance <= 1 {
+		return tree.rotateRightOverLeftLocked(parent, node, left, rightHeight, leftLeftHeight, leftRight, leftRightLeftHeight)
+	}
+	// A double rotation would leave the left child unbalanced, which means
+	// that the right child of the left one is unbalanced now: it is repaired first
+	return leftRight
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) rebalanceToLeftLocked(parent, node, right *Node[T, K], leftHeight int64) *Node[T, K] {
+	right.lock()
+	defer right.unlock()
+
+	if leftHeight-right.height.Load() >= -1 {
+		return node
+	}
+	rightLeft := right.left.Load()
+	rightRightHeight := right.right.Load().heightOf()
+	if rightLeft == nil {
+		return tree.rotateLeftLocked(parent, node, leftHeight, right, nil, 0, rightRightHeight)
+	}
+
+	rightLeft.lock()
+	rightLeftHeight := rightLeft.height.Load()
+	if rightRightHeight >= rightLeftHeight {
+		next := tree.rotateLeftLocked(parent, node, leftHeight, right, rightLeft, rightLeftHeight, rightRightHeight)
+		rightLeft.unlock()
+		return next
+	}
+
+	rightLeftLeft, rightLeftRight := rightLeft.left.Load(), rightLeft.right.Load()
+	lockAll(rightLeftLeft, rightLeftRight)
+	defer unlockAll(rightLeftLeft, rightLeftRight, rightLeft)
+	rightLeftRightHeight := rightLeftRight.heightOf()
+	balance := rightRightHeight - rightLeftRightHeight
+	if balance >= -1 && balance <= 1 {
+		return tree.rotateLeftOverRightLocked(parent, node, leftHeight, right, rightLeft, rightRightHeight, rightLeftRightHeight)
+	}
+	return rightLeft
+}
+
+func lockAll[T any, K cmp.Ordered](nodes ...*Node[T, K]) {
+	for _, node := range nodes {
+		if node != nil {
+			node.lock()
+		}
+	}
+}
+
+func unlockAll[T any, K cmp.Ordered](nodes ...*Node[T, K]) {
+	for _, node := range nodes {
+		if node != nil {
+			node.unlock()
+		}
+	}
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) rotateRightLocked(parent, node, left *Node[T, K], rightHeight, leftLeftHeight int64, leftRight *Node[T, K], leftRightHeight int64) *Node[T, K] {
+	version := node.version.Load()
+	parentLeft := parent.left.Load()
+	node.version.Store(version | shrinking)
+
+	node.left.Store(leftRight)
+	if leftRight != nil {
+		leftRight.parent.Store(node)
+	}
+	left.right.Store(node)
+	node.parent.Store(left)
+	if parentLeft == node {
+		parent.left.Store(left)
+	} else {
+		parent.right.Store(left)
+	}
+	left.parent.Store(parent)
+
+	nodeHeight := 1 + max(leftRightHeight, rightHeight)
+	node.height.Store(nodeHeight)
+	left.height.Store(1 + max(leftLeftHeight, nodeHeight))
+	node.version.Store(version&^shrinking + shrinkCountIncr)
+
+	// Return a node that may be damaged by the rotation
+	balance := leftRightHeight - rightHeight
+	if balance < -1 || balance > 1 {
+		return node
+	}
+	if (leftRight == nil || rightHeight == 0) && node.value.Load() == nil {
+		return node
+	}
+	balance = leftLeftHeight - nodeHeight
+	if balance < -1 || balance > 1 {
+		return left
+	}
+	if leftLeftHeight == 0 && left.value.Load() == nil {
+		return left
+	}
+	return tree.fixHeightLocked(parent)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) rotateLeftLocked(parent, node *Node[T, K], leftHeight int64, right, rightLeft *Node[T, K], rightLeftHeight, rightRightHeight int64) *Node[T, K] {
+	version := node.version.Load()
+	parentLeft := parent.left.Load()
+	node.version.Store(version | shrinking)
+
+	node.right.Store(rightLeft)
+	if rightLeft != nil {
+		rightLeft.parent.Store(node)
+	}
+	right.left.Store(node)
+	node.parent.Store(right)
+	if parentLeft == node {
+		parent.left.Store(right)
+	} else {
+		parent.right.Store(right)
+	}
+	right.parent.Store(parent)
+
+	nodeHeight := 1 + max(leftHeight, rightLeftHeight)
+	node.height.Store(nodeHeight)
+	right.height.Store(1 + max(nodeHeight, rightRightHeight))
+	node.version.Store(version&^shrinking + shrinkCountIncr)
+
+	balance := rightLeftHeight - leftHeight
+	if balance < -1 || balance > 1 {
+		return node
+	}
+	if (rightLeft == nil || leftHeight == 0) && node.value.Load() == nil {
+		return node
+	}
+	balance = rightRightHeight - nodeHeight
+	if balance < -1 || balance > 1 {
+		return right
+	}
+	if rightRightHeight == 0 && right.value.Load() == nil {
+		return right
+	}
+	return tree.fixHeightLocked(parent)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) rotateRightOverLeftLocked(parent, node, left *Node[T, K], rightHeight, leftLeftHeight int64, leftRight *Node[T, K], leftRightLeftHeight int64) *Node[T, K] {
+	version := node.version.Load()
+	leftVersion := left.version.Load()
+	parentLeft := parent.left.Load()
+	leftRightLeft := leftRight.left.Load()
+	leftRightRight := leftRight.right.Load()
+	leftRightRightHeight := leftRightRight.heightOf()
+	node.version.Store(version | shrinking)
+	left.version.Store(leftVersion | shrinking)
+
+	node.left.Store(leftRightRight)
+	if leftRightRight != nil {
+		leftRightRight.parent.Store(node)
+	}
+	left.right.Store(leftRightLeft)
+	if leftRightLeft != nil {
+		leftRightLeft.parent.Store(left)
+	}
+	leftRight.left.Store(left)
+	left.parent.Store(leftRight)
+	leftRight.right.Store(node)
+	node.parent.Store(leftRight)
+	if parentLeft == node {
+		parent.left.Store(leftRight)
+	} else {
+		parent.right.Store(leftRight)
+	}
+	leftRight.parent.Store(parent)
+
+	nodeHeight := 1 + max(leftRightRightHeight, rightHeight)
+	node.height.Store(nodeHeight)
+	leftHeight := 1 + max(leftLeftHeight, leftRightLeftHeight)
+	left.height.Store(leftHeight)
+	leftRight.height.Store(1 + max(leftHeight, nodeHeight))
+	node.version.Store(version&^shrinking + shrinkCountIncr)
+	left.version.Store(leftVersion&^shrinking + shrinkCountIncr)
+
+	balance := leftRightRightHeight - rightHeight
+	if balance < -1 || balance > 1 {
+		return node
+	}
+	if (leftRightRight == nil || rightHeight == 0) && node.value.Load() == nil {
+		return node
+	}
+	if (leftRightLeft == nil || leftLeftHeight == 0) && left.value.Load() == nil {
+		return left
+	}
+	balance = leftHeight - nodeHeight
+	if balance < -1 || balance > 1 {
+		return leftRight
+	}
+	return tree.fixHeightLocked(parent)
+}
+
+func (tree *OptimisticAVLSyncTree[T, K]) rotateLeftOverRightLocked(parent, node *Node[T, K], leftHeight int64, right, rightLeft *Node[T, K], rightRightHeight, rightLeftRightHeight int64) *Node[T, K] {
+	version := node.version.Load()
+	rightVersion := right.version.Load()
+	parentLeft := parent.left.Load()
+	rightLeftLeft := rightLeft.left.Load()
+	rightLeftLeftHeight := rightLeftLeft.heightOf()
+	rightLeftRight := rightLeft.right.Load()
+	node.version.Store(version | shrinking)
+	right.version.Store(rightVersion | shrinking)
+
+	node.right.Store(rightLeftLeft)
+	if rightLeftLeft != nil {
+		rightLeftLeft.parent.Store(node)
+	}
+	right.left.Store(rightLeftRight)
+	if rightLeftRight != nil {
+		rightLeftRight.parent.Store(right)
+	}
+	rightLeft.right.Store(right)
+	right.parent.Store(rightLeft)
+	rightLeft.left.Store(node)
+	node.parent.Store(rightLeft)
+	if parentLeft == node {
+		parent.left.Store(rightLeft)
+	} else {
+		parent.right.Store(rightLeft)
+	}
+	rightLeft.parent.Store(parent)
+
+	nodeHeight := 1 + max(leftHeight, rightLeftLeftHeight)
+	node.height.Store(nodeHeight)
+	rightHeight := 1 + max(rightLeftRightHeight, rightRightHeight)
+	right.height.Store(rightHeight)
+	rightLeft.height.Store(1 + max(nodeHeight, rightHeight))
+	node.version.Store(version&^shrinking + shrinkCountIncr)
+	right.version.Store(rightVersion&^shrinking + shrinkCountIncr)
+
+	balance := rightLeftLeftHeight - leftHeight
+	if balance < -1 || balance > 1 {
+		return node
+	}
+	if (rightLeftLeft == nil || leftHeight == 0) && node.value.Load() == nil {
+		return node
+	}
+	if (rightLeftRight == nil || rightRightHeight == 0) && right.value.Load() == nil {
+		return right
+	}
+	balance = rightHeight - nodeHeight
+	if balance < -1 || balance > 1 {
+		return rightLeft
+	}
+	return tree.fixHeightLocked(parent)
+}
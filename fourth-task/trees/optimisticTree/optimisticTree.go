@@ -1,32 +1,51 @@
 package optimisticTree
 
 import (
+	"bst/trees"
 	"cmp"
 	"fmt"
+	"iter"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 type OptimisticSyncTree[T any, K cmp.Ordered] struct {
-	root  *Node[T, K]
-	mutex sync.Mutex
+	root        atomic.Pointer[Node[T, K]]
+	mutex       sync.Mutex
+	relocations trees.Relocations
 }
 
+// The fields that Find reads without locks are atomic. The key and the value of a node
+// are replaced together, when a two-children Remove moves the successor into the node.
 type Node[T any, K cmp.Ordered] struct {
+	pair    atomic.Pointer[pair[T, K]]
+	left    atomic.Pointer[Node[T, K]]
+	right   atomic.Pointer[Node[T, K]]
+	mutex   sync.Mutex
+	size    atomic.Int64 // The amount of nodes in the subtree.
+	removed atomic.Bool  // Set under the node lock, before the node is unlinked from the tree.
+}
+
+type pair[T any, K cmp.Ordered] struct {
 	key   K
 	value T
-	left  *Node[T, K]
-	right *Node[T, K]
-	mutex sync.Mutex
 }
 
 func FreshOptimisticSyncTree[T any, K cmp.Ordered]() *OptimisticSyncTree[T, K] {
 	return &OptimisticSyncTree[T, K]{
-		root:  nil,
 		mutex: sync.Mutex{},
 	}
 }
 
+func (node *Node[T, K]) key() K {
+	return node.pair.Load().key
+}
+
+func (node *Node[T, K]) value() T {
+	return node.pair.Load().value
+}
+
 func (tree *OptimisticSyncTree[T, K]) lock() {
 	tree.mutex.Lock()
 }
@@ -43,70 +62,99 @@ func (node *Node[T, K]) unlock() {
 	node.mutex.Unlock()
 }
 
-func (tree *OptimisticSyncTree[T, K]) findWithParent(key K) (*Node[T, K], *Node[T, K]) {
+func (tree *OptimisticSyncTree[T, K]) findWithParent(key K, path *[]*Node[T, K]) (*Node[T, K], *Node[T, K]) {
+	// The ancestors of the found node are appended to path, unless it is nil
 	for {
+		epoch := tree.relocations.Wait()
 
-		tree.lock()
-		if tree.root == nil {
-			return nil, nil
+		// Try to find a node without locks
+		if path != nil {
+			*path = (*path)[:0]
 		}
-
 		parent := (*Node[T, K])(nil)
-		current := tree.root
-
-		// Try to find a node without locks
-		for current != nil && current.key != key {
-			if parent == nil {
-				tree.unlock()
-			}
+		current := tree.root.Load()
+		for current != nil && current.key() != key {
 			parent = current
-			if cmp.Less(key, current.key) {
-				current = current.left
+			if path != nil {
+				*path = append(*path, current)
+			}
+			if cmp.Less(key, current.key()) {
+				current = current.left.Load()
 			} else {
-				current = current.right
+				current = current.right.Load()
 			}
 		}
 
 		// Locating the found nodes
 		if parent != nil {
 			parent.lock()
+		} else {
+			tree.lock()
 		}
-
 		if current != nil {
 			current.lock()
 		}
 
-		// Validate the path
-		validateParent := (*Node[T, K])(nil)
-		validateCurrent := tree.root
+		// Only the locked nodes are validated, as in the lazy list: the parent must still be
+		// linked and point to current. Without relocations a key keeps its path, unless
+		// a node of the path is unlinked, which moves its subtree up
+		valid := tree.relocations.Unchanged(epoch) && (current == nil || current.key() == key)
+		if parent == nil {
+			valid = valid && tree.root.Load() == current
+		} else if cmp.Less(key, parent.key()) {
+			valid = valid && !parent.removed.Load() && parent.left.Load() == current
+		} else {
+			valid = valid && !parent.removed.Load() && parent.right.Load() == current
+		}
+		if valid {
+			return current, parent
+		}
 
-		for validateCurrent != nil && validateCurrent != current && cmp.Compare(validateCurrent.key, key) != 0 {
-			validateParent = validateCurrent
-			if cmp.Less(key, validateCurrent.key) {
-				validateCurrent = validateCurrent.left
-			} else {
-				validateCurrent = validateCurrent.right
-			}
+		if current != nil {
+			current.unlock()
 		}
+		if parent != nil {
+			parent.unlock()
+		} else {
+			tree.unlock()
+		}
+	}
+}
 
-		// Checking the validity of current and parent nodes
-		if validateCurrent != current || validateParent != parent {
-			if current != nil {
-				current.unlock()
-			}
-			if parent != nil {
-				parent.unlock()
+func (tree *OptimisticSyncTree[T, K]) Find(key K) (T, bool) {
+	// Find searches without locks. A found key is linearized at the load of its pair, when the node
+	// is still linked, because the node is marked as removed before it is unlinked. A missing key is
+	// linearized during the search, unless a two-children Remove has moved a successor meanwhile,
+	// which may have carried the key above the search, then the locking search decides
+	if tree == nil {
+		return *(new(T)), false
+	}
+	epoch := tree.relocations.Epoch()
+	current := tree.root.Load()
+	for current != nil {
+		pair := current.pair.Load()
+		comp := cmp.Compare(key, pair.key)
+		if comp == 0 {
+			if !current.removed.Load() {
+				return pair.value, true
 			}
-			continue
+			break
 		}
-
-		return current, parent
+		if comp == -1 {
+			current = current.left.Load()
+		} else {
+			current = current.right.Load()
+		}
+	}
+	if current == nil && tree.relocations.Unchanged(epoch) {
+		return *(new(T)), false
 	}
+	return tree.findLocked(key)
 }
 
-func (tree *OptimisticSyncTree[T, K]) Find(key K) (T, bool) {
+func (tree *OptimisticSyncTree[T, K]) findLocked(key K) (T, bool) {
 	// Use a helper method for optimistic searching
-	node, parent := tree.findWithParent(key)
+	node, parent := tree.findWithParent(key, nil)
 
 	// If the node is not found
 	if node == nil {
@@ -120,7 +168,7 @@ func (tree *OptimisticSyncTree[T, K]) Find(key K) (T, bool) {
 	}
 
 	// Read the value from the node
-	value := node.value
+	value := node.value()
 
 	// Remove locks
 	node.unlock()
@@ -134,12 +182,16 @@ func (tree *OptimisticSyncTree[T, K]) Find(key K) (T, bool) {
 }
 
 func (tree *OptimisticSyncTree[T, K]) Insert(key K, value T) {
+	if tree == nil {
+		return
+	}
 	// Use the findWithParent helper method to find a node and its parent
-	node, parent := tree.findWithParent(key)
+	path := []*Node[T, K]{}
+	node, parent := tree.findWithParent(key, &path)
 
 	// If a node with such a key already exists, update its value
 	if node != nil {
-		node.value = value
+		node.pair.Store(&pair[T, K]{key: key, value: value})
 		node.unlock()
 		if parent != nil {
 			parent.unlock()
@@ -149,25 +201,23 @@ func (tree *OptimisticSyncTree[T, K]) Insert(key K, value T) {
 		return
 	}
 
-	newNode := &Node[T, K]{
-		key:   key,
-		value: value,
-		left:  nil,
-		right: nil,
-	}
+	newNode := &Node[T, K]{}
+	newNode.pair.Store(&pair[T, K]{key: key, value: value})
+	newNode.size.Store(1)
 
 	// Insert a new node into the tree
 	if parent == nil {
 		// The tree is empty, insert a new node as the root
-		tree.root = newNode
+		tree.root.Store(newNode)
 		tree.unlock()
 	} else {
 		// Insert a new node as a child of the parent
-		if cmp.Less(key, parent.key) {
-			parent.left = newNode
+		if cmp.Less(key, parent.key()) {
+			parent.left.Store(newNode)
 		} else {
-			parent.right = newNode
+			parent.right.Store(newNode)
 		}
+		grow(path, 1)
 		parent.unlock()
 	}
 
@@ -175,8 +225,12 @@ func (tree *OptimisticSyncTree[T, K]) Insert(key K, value T) {
 }
 
 func (tree *OptimisticSyncTree[T, K]) Remove(key K) bool {
+	if tree == nil {
+		return false
+	}
 	// Use the findWithParent helper method to find a node and its parent
-	node, parent := tree.findWithParent(key)
+	path := []*Node[T, K]{}
+	node, parent := tree.findWithParent(key, &path)
 
 	// If the node is not found, do nothing
 	if node == nil {
@@ -198,62 +252,74 @@ func (tree *OptimisticSyncTree[T, K]) Remove(key K) bool {
 	}()
 
 	// If the node has no children
-	if node.left == nil && node.right == nil {
+	if node.left.Load() == nil && node.right.Load() == nil {
+		node.removed.Store(true)
 		if parent == nil {
-			tree.root = nil
-		} else if cmp.Less(key, parent.key) {
-			parent.left = nil
+			tree.root.Store(nil)
+		} else if cmp.Less(key, parent.key()) {
+			parent.left.Store(nil)
 		} else {
-			parent.right = nil
+			parent.right.Store(nil)
 		}
+		grow(path, -1)
 		return true
 	}
 
 	// If the node has one child
-	if node.left == nil || node.right == nil {
+	if node.left.Load() == nil || node.right.Load() == nil {
 		var child *Node[T, K]
-		if node.left != nil {
-			child = node.left
+		if node.left.Load() != nil {
+			child = node.left.Load()
 		} else {
-			child = node.right
+			child = node.right.Load()
 		}
+		node.removed.Store(true)
 
 		if parent == nil {
-			tree.root = child
-		} else if cmp.Less(key, parent.key) {
-			parent.left = child
+			tree.root.Store(child)
+		} else if cmp.Less(key, parent.key()) {
+			parent.left.Store(child)
 		} else {
-			parent.right = child
+			parent.right.Store(child)
 		}
+		grow(path, -1)
 		return true
 	}
 
 	// If the node has two children
-	if node.left != nil && node.right != nil {
+	if node.left.Load() != nil && node.right.Load() != nil {
 		successorParent := node
-		successor := node.right
+		successor := node.right.Load()
 		successor.lock()
 
-		for successor.left != nil {
-			successor.left.lock()
+		// Every node between the removed one and the successor loses the successor
+		grow(path, -1)
+		node.size.Add(-1)
+		for successor.left.Load() != nil {
+			successor.size.Add(-1)
+			successor.left.Load().lock()
 			if successorParent != node {
 				successorParent.unlock()
 			}
 			successorParent = successor
-			successor = successor.left
+			successor = successor.left.Load()
 		}
 
+		// The successor parent stays locked until the move is finished, so that no insertion
+		// validates against it while the successor key is on its way up
+		tree.relocations.Begin()
+		successor.removed.Store(true)
 		if successorParent != node {
-			successorParent.left = successor.right
-			node.key = successor.key
-			node.value = successor.value
-			successorParent.unlock()
+			successorParent.left.Store(successor.right.Load())
 		} else {
-			successorParent.right = successor.right
-			node.key = successor.key
-			node.value = successor.value
+			successorParent.right.Store(successor.right.Load())
 		}
+		node.pair.Store(successor.pair.Load())
+		tree.relocations.End()
 
+		if successorParent != node {
+			successorParent.unlock()
+		}
 		successor.unlock()
 		return true
 	}
@@ -262,10 +328,13 @@ func (tree *OptimisticSyncTree[T, K]) Remove(key K) bool {
 }
 
 func (tree *OptimisticSyncTree[T, K]) Print() {
+	if tree == nil {
+		return
+	}
 	tree.lock()
 	defer tree.unlock()
 
-	tree.printHelper(tree.root, 0)
+	tree.printHelper(tree.root.Load(), 0)
 }
 
 func (tree *OptimisticSyncTree[T, K]) printHelper(node *Node[T, K], indent int) {
@@ -274,43 +343,329 @@ func (tree *OptimisticSyncTree[T, K]) printHelper(node *Node[T, K], indent int)
 	}
 
 	// Print the right subtree with indentation
-	tree.printHelper(node.right, indent+4)
+	tree.printHelper(node.right.Load(), indent+4)
 
 	// Print current node
 	fmt.Print(strings.Repeat(" ", indent))
-	fmt.Printf("%v\n", node.key)
+	fmt.Printf("%v\n", node.key())
 
 	// Print left subtree with indentation
-	tree.printHelper(node.left, indent+4)
+	tree.printHelper(node.left.Load(), indent+4)
 }
 
 func (tree *OptimisticSyncTree[T, K]) CountNodes() int {
 	// CountNodes counts the number of nodes in the tree
+	if tree == nil {
+		return 0
+	}
 	tree.lock()
 	defer tree.unlock()
-	return tree.countNodesRecursive(tree.root)
+	return tree.countNodesRecursive(tree.root.Load())
 }
 
 func (tree *OptimisticSyncTree[T, K]) countNodesRecursive(node *Node[T, K]) int {
 	if node == nil {
 		return 0
 	}
-	return 1 + tree.countNodesRecursive(node.left) + tree.countNodesRecursive(node.right)
+	return 1 + tree.countNodesRecursive(node.left.Load()) + tree.countNodesRecursive(node.right.Load())
 }
 
 func (tree *OptimisticSyncTree[T, K]) IsValid() bool {
 	// IsValid checks if the tree is a valid binary search tree
+	if tree == nil {
+		return true
+	}
 	tree.lock()
 	defer tree.unlock()
-	return tree.isValidBSTRecursive(tree.root, nil, nil)
+	_, validSizes := tree.isValidSizeRecursive(tree.root.Load())
+	return tree.isValidBSTRecursive(tree.root.Load(), nil, nil) && validSizes
 }
 
 func (tree *OptimisticSyncTree[T, K]) isValidBSTRecursive(node *Node[T, K], min *K, max *K) bool {
 	if node == nil {
 		return true
 	}
-	if (min != nil && cmp.Compare(node.key, *min) <= 0) || (max != nil && cmp.Compare(node.key, *max) >= 0) {
+	key := node.key()
+	if (min != nil && cmp.Compare(key, *min) <= 0) || (max != nil && cmp.Compare(key, *max) >= 0) {
 		return false
 	}
-	return tree.isValidBSTRecursive(node.left, min, &node.key) && tree.isValidBSTRecursive(node.right, &node.key, max)
+	return tree.isValidBSTRecursive(node.left.Load(), min, &key) && tree.isValidBSTRecursive(node.right.Load(), &key, max)
+}
+
+func (tree *OptimisticSyncTree[T, K]) isValidSizeRecursive(node *Node[T, K]) (int, bool) {
+	// Returns the real size of the subtree and whether all the stored sizes match
+	if node == nil {
+		return 0, true
+	}
+	left, validLeft := tree.isValidSizeRecursive(node.left.Load())
+	right, validRight := tree.isValidSizeRecursive(node.right.Load())
+	return 1 + left + right, validLeft && validRight && node.sizeOf() == 1+left+right
+}
+
+// Iteration. A cursor keeps the ancestors whose keys are still ahead of it, the nearest one on top,
+// and reads them without locks, like Find: keys that are inserted or removed concurrently may or may
+// not be yielded, but the keys are strictly increasing, each yielded pair was present at some moment
+// and a key present during the whole iteration is yielded exactly once. An unlinked node keeps its
+// children, so the cursor still reaches the remaining keys through it, only the move of a successor
+// by a two-children Remove sends the cursor back to the root. A full walk reads O(n + h) nodes.
+
+func (tree *OptimisticSyncTree[T, K]) Range(lo, hi K) iter.Seq2[K, T] {
+	if tree == nil {
+		return trees.Empty[T, K]()
+	}
+	return trees.Ascend(tree.seek, &lo, &hi)
+}
+
+func (tree *OptimisticSyncTree[T, K]) All() iter.Seq2[K, T] {
+	if tree == nil {
+		return trees.Empty[T, K]()
+	}
+	return trees.Ascend(tree.seek, nil, nil)
+}
+
+func (tree *OptimisticSyncTree[T, K]) Keys() iter.Seq[K] {
+	return trees.KeysOf(tree.All())
+}
+
+func (tree *OptimisticSyncTree[T, K]) Values() iter.Seq[T] {
+	return trees.ValuesOf(tree.All())
+}
+
+func (tree *OptimisticSyncTree[T, K]) seek(bound *K, inclusive bool) trees.Step[T, K] {
+	epoch := tree.relocations.Wait()
+	ancestors := []*Node[T, K]{}
+
+	descend := func(current *Node[T, K]) {
+		// Pushes the path down to the smallest key beyond bound
+		for current != nil {
+			if trees.Beyond(current.key(), bound, inclusive, true) {
+				ancestors = append(ancestors, current)
+				current = current.left.Load()
+			} else {
+				current = current.right.Load()
+			}
+		}
+	}
+	descend(tree.root.Load())
+
+	return func() (K, T, bool, bool) {
+		for len(ancestors) > 0 {
+			node := ancestors[len(ancestors)-1]
+			ancestors = ancestors[:len(ancestors)-1]
+
+			pair, removed := node.pair.Load(), node.removed.Load()
+			descend(node.right.Load())
+
+			if !tree.relocations.Unchanged(epoch) {
+				return *(new(K)), *(new(T)), false, true
+			}
+			if !removed {
+				return pair.key, pair.value, true, false
+			}
+		}
+		return *(new(K)), *(new(T)), false, !tree.relocations.Unchanged(epoch)
+	}
+}
+
+// Ordered queries. Like findWithParent, they search without locks and then lock and validate
+// the found nodes. A search that overlaps the move of a successor by a two-children Remove starts over.
+
+func (tree *OptimisticSyncTree[T, K]) Min() (K, T, bool) {
+	return tree.nearest(nil, true, true)
+}
+
+func (tree *OptimisticSyncTree[T, K]) Max() (K, T, bool) {
+	return tree.nearest(nil, true, false)
+}
+
+func (tree *OptimisticSyncTree[T, K]) Floor(key K) (K, T, bool) {
+	return tree.nearest(&key, true, false)
+}
+
+func (tree *OptimisticSyncTree[T, K]) Ceiling(key K) (K, T, bool) {
+	return tree.nearest(&key, true, true)
+}
+
+func (tree *OptimisticSyncTree[T, K]) Predecessor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, false)
+}
+
+func (tree *OptimisticSyncTree[T, K]) Successor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, true)
+}
+
+func (tree *OptimisticSyncTree[T, K]) nearest(bound *K, inclusive, ascending bool) (K, T, bool) {
+	// The closest key beyond bound in the given direction
+	var key K
+	var value T
+	if tree == nil {
+		return key, value, false
+	}
+	for {
+		epoch := tree.relocations.Wait()
+
+		// Try to find the nodes without locks
+		candidate, last := tree.searchNearest(bound, inclusive, ascending)
+		if last == nil {
+			tree.lock()
+			empty := tree.root.Load() == nil
+			tree.unlock()
+			if empty {
+				return key, value, false
+			}
+			continue
+		}
+
+		// Locking the found nodes, the candidate is an ancestor of the last node
+		if candidate != nil && candidate != last {
+			candidate.lock()
+		}
+		last.lock()
+
+		// Validate the path: nothing can be inserted below the locked last node
+		validateCandidate, validateLast := tree.searchNearest(bound, inclusive, ascending)
+		valid := validateCandidate == candidate && validateLast == last && tree.relocations.Unchanged(epoch)
+		found := candidate != nil
+		if found {
+			key, value = candidate.key(), candidate.value()
+		}
+
+		last.unlock()
+		if candidate != nil && candidate != last {
+			candidate.unlock()
+		}
+		if valid {
+			return key, value, found
+		}
+	}
+}
+
+func (tree *OptimisticSyncTree[T, K]) searchNearest(bound *K, inclusive, ascending bool) (*Node[T, K], *Node[T, K]) {
+	// Returns the node with the closest key and the last node of the search path
+	candidate := (*Node[T, K])(nil)
+	last := (*Node[T, K])(nil)
+	for current := tree.root.Load(); current != nil; {
+		last = current
+		if trees.Beyond(current.key(), bound, inclusive, ascending) {
+			candidate = current
+			current = current.near(ascending)
+		} else {
+			current = current.far(ascending)
+		}
+	}
+	return candidate, last
+}
+
+func (node *Node[T, K]) near(ascending bool) *Node[T, K] {
+	if ascending {
+		return node.left.Load()
+	}
+	return node.right.Load()
+}
+
+func (node *Node[T, K]) far(ascending bool) *Node[T, K] {
+	if ascending {
+		return node.right.Load()
+	}
+	return node.left.Load()
+}
+
+// Order statistics. The sizes of the ancestors recorded by findWithParent are updated after
+// an insertion or a removal, so they are exact once the concurrent updates are finished.
+// Both queries search without locks and then validate the search under the lock of its last node.
+
+func (tree *OptimisticSyncTree[T, K]) Rank(key K) int {
+	if tree == nil {
+		return 0
+	}
+	for {
+		epoch := tree.relocations.Wait()
+
+		rank, last := tree.searchRank(key)
+		if last == nil {
+			return 0
+		}
+		last.lock()
+		validateRank, validateLast := tree.searchRank(key)
+		last.unlock()
+
+		if validateRank == rank && validateLast == last && tree.relocations.Unchanged(epoch) {
+			return rank
+		}
+	}
+}
+
+func (tree *OptimisticSyncTree[T, K]) searchRank(key K) (int, *Node[T, K]) {
+	// Returns the amount of keys less than the given one and the last node of the search path
+	rank := 0
+	last := (*Node[T, K])(nil)
+	for current := tree.root.Load(); current != nil; {
+		last = current
+		if cmp.Less(current.key(), key) {
+			rank += 1 + current.left.Load().sizeOf()
+			current = current.right.Load()
+		} else {
+			current = current.left.Load()
+		}
+	}
+	return rank, last
+}
+
+func (tree *OptimisticSyncTree[T, K]) Select(rank int) (K, T, bool) {
+	var key K
+	var value T
+	if tree == nil {
+		return key, value, false
+	}
+	for {
+		epoch := tree.relocations.Wait()
+
+		if rank < 0 || rank >= tree.root.Load().sizeOf() {
+			return key, value, false
+		}
+		node := tree.searchSelect(rank)
+		if node == nil {
+			// The sizes were changed during the search
+			continue
+		}
+
+		node.lock()
+		valid := tree.searchSelect(rank) == node && tree.relocations.Unchanged(epoch)
+		key, value = node.key(), node.value()
+		node.unlock()
+		if valid {
+			return key, value, true
+		}
+	}
+}
+
+func (tree *OptimisticSyncTree[T, K]) searchSelect(rank int) *Node[T, K] {
+	// Returns the node with the given rank
+	current := tree.root.Load()
+	for current != nil {
+		left := current.left.Load().sizeOf()
+		if rank == left {
+			return current
+		}
+		if rank < left {
+			current = current.left.Load()
+		} else {
+			rank -= left + 1
+			current = current.right.Load()
+		}
+	}
+	return nil
+}
+
+func grow[T any, K cmp.Ordered](path []*Node[T, K], delta int64) {
+	for _, node := range path {
+		node.size.Add(delta)
+	}
+}
+
+func (node *Node[T, K]) sizeOf() int {
+	if node == nil {
+		return 0
+	}
+	return int(node.size.Load())
 }
@@ -0,0 +1,437 @@
+package redBlackTree
+
+import (
+	"bst/trees"
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+)
+
+// Left-leaning red-black tree behind a single mutex, like CoarseGrainedSyncTree. Red links
+// lean left and never follow each other, every path from the root to a leaf has the same amount
+// of black links, so the height of the tree stays below 2 * log2(n + 1).
+
+type RedBlackSyncTree[T any, K cmp.Ordered] struct {
+	root  *Node[T, K]
+	mutex sync.Mutex
+}
+
+type Node[T any, K cmp.Ordered] struct {
+	key   K
+	value T
+	left  *Node[T, K]
+	right *Node[T, K]
+	red   bool // The color of the link from the parent.
+	size  int  // The amount of nodes in the subtree.
+}
+
+func FreshRedBlackSyncTree[T any, K cmp.Ordered]() *RedBlackSyncTree[T, K] {
+	return &RedBlackSyncTree[T, K]{
+		root:  nil,
+		mutex: sync.Mutex{},
+	}
+}
+
+func (tree *RedBlackSyncTree[T, K]) lock() {
+	tree.mutex.Lock()
+}
+
+func (tree *RedBlackSyncTree[T, K]) unlock() {
+	tree.mutex.Unlock()
+}
+
+func (tree *RedBlackSyncTree[T, K]) Insert(key K, value T) {
+	if tree == nil {
+		return
+	}
+	tree.lock()
+	defer tree.unlock()
+	tree.root = tree.insertRecursive(tree.root, key, value)
+	tree.root.red = false
+}
+
+func (tree *RedBlackSyncTree[T, K]) insertRecursive(node *Node[T, K], key K, value T) *Node[T, K] {
+	if node == nil {
+		return &Node[T, K]{key: key, value: value, red: true, size: 1}
+	}
+	comp := cmp.Compare(key, node.key)
+	if comp == -1 {
+		node.left = tree.insertRecursive(node.left, key, value)
+	} else if comp == 1 {
+		node.right = tree.insertRecursive(node.right, key, value)
+	} else {
+		// Key already exists, update value
+		node.value = value
+	}
+	return node.balance()
+}
+
+func (tree *RedBlackSyncTree[T, K]) Find(key K) (T, bool) {
+	if tree == nil {
+		return *(new(T)), false
+	}
+	tree.lock()
+	defer tree.unlock()
+	value, found := tree.findRecursive(tree.root, key)
+	return value, found
+}
+
+func (tree *RedBlackSyncTree[T, K]) findRecursive(node *Node[T, K], key K) (T, bool) {
+	var emptValue T
+	if node == nil {
+		return emptValue, false
+	}
+	comp := cmp.Compare(key, node.key)
+	if comp == -1 {
+		return tree.findRecursive(node.left, key)
+	} else if comp == 1 {
+		return tree.findRecursive(node.right, key)
+	} else {
+		return node.value, true
+	}
+}
+
+func (tree *RedBlackSyncTree[T, K]) Remove(key K) bool {
+	if tree == nil {
+		return false
+	}
+	tree.lock()
+	defer tree.unlock()
+	if _, found := tree.findRecursive(tree.root, key); !found {
+		return false
+	}
+
+	// The removal moves a red link down the search path, the root starts it
+	if !tree.root.left.isRed() && !tree.root.right.isRed() {
+		tree.root.red = true
+	}
+	tree.root = tree.removeRecursive(tree.root, key)
+	if tree.root != nil {
+		tree.root.red = false
+	}
+	return true
+}
+
+func (tree *RedBlackSyncTree[T, K]) removeRecursive(node *Node[T, K], key K) *Node[T, K] {
+	// The key is in the subtree and the node or its left child is red
+	if cmp.Less(key, node.key) {
+		if !node.left.isRed() && !node.left.left.isRed() {
+			node = node.moveRedLeft()
+		}
+		node.left = tree.removeRecursive(node.left, key)
+		return node.balance()
+	}
+
+	if node.left.isRed() {
+		node = node.rotateRight()
+	}
+	if key == node.key && node.right == nil {
+		return nil
+	}
+	if !node.right.isRed() && !node.right.left.isRed() {
+		node = node.moveRedRight()
+	}
+	if key == node.key {
+		// Copy the inorder successor's content to this node and delete the successor
+		successor := tree.minValueNode(node.right)
+		node.key = successor.key
+		node.value = successor.value
+		node.right = tree.removeMinRecursive(node.right)
+	} else {
+		node.right = tree.removeRecursive(node.right, key)
+	}
+	return node.balance()
+}
+
+func (tree *RedBlackSyncTree[T, K]) removeMinRecursive(node *Node[T, K]) *Node[T, K] {
+	if node.left == nil {
+		return nil
+	}
+	if !node.left.isRed() && !node.left.left.isRed() {
+		node = node.moveRedLeft()
+	}
+	node.left = tree.removeMinRecursive(node.left)
+	return node.balance()
+}
+
+func (tree *RedBlackSyncTree[T, K]) minValueNode(node *Node[T, K]) *Node[T, K] {
+	current := node
+	for current.left != nil {
+		current = current.left
+	}
+	return current
+}
+
+// Rebalancing, as in Sedgewick's left-leaning red-black trees.
+
+func (node *Node[T, K]) balance() *Node[T, K] {
+	// Fixes right-leaning and consecutive red links on the way up
+	if node.right.isRed() && !node.left.isRed() {
+		node = node.rotateLeft()
+	}
+	if node.left.isRed() && node.left.left.isRed() {
+		node = node.rotateRight()
+	}
+	if node.left.isRed() && node.right.isRed() {
+		node.flipColors()
+	}
+	node.size = 1 + node.left.Size() + node.right.Size()
+	return node
+}
+
+func (node *Node[T, K]) moveRedLeft() *Node[T, K] {
+	// Makes the left child or one of its children red
+	node.flipColors()
+	if node.right.left.isRed() {
+		node.right = node.right.rotateRight()
+		node = node.rotateLeft()
+		node.flipColors()
+	}
+	return node
+}
+
+func (node *Node[T, K]) moveRedRight() *Node[T, K] {
+	// Makes the right child or one of its children red
+	node.flipColors()
+	if node.left.left.isRed() {
+		node = node.rotateRight()
+		node.flipColors()
+	}
+	return node
+}
+
+func (node *Node[T, K]) rotateLeft() *Node[T, K] {
+	right := node.right
+	node.right = right.left
+	right.left = node
+	right.red = node.red
+	node.red = true
+	right.size = node.size
+	node.size = 1 + node.left.Size() + node.right.Size()
+	return right
+}
+
+func (node *Node[T, K]) rotateRight() *Node[T, K] {
+	left := node.left
+	node.left = left.right
+	left.right = node
+	left.red = node.red
+	node.red = true
+	left.size = node.size
+	node.size = 1 + node.left.Size() + node.right.Size()
+	return left
+}
+
+func (node *Node[T, K]) flipColors() {
+	node.red = !node.red
+	node.left.red = !node.left.red
+	node.right.red = !node.right.red
+}
+
+func (node *Node[T, K]) isRed() bool {
+	return node != nil && node.red
+}
+
+func (tree *RedBlackSyncTree[T, K]) Print() {
+	if tree == nil {
+		return
+	}
+	tree.mutex.Lock()
+	defer tree.mutex.Unlock()
+
+	tree.printHelper(tree.root, 0)
+}
+
+func (tree *RedBlackSyncTree[T, K]) printHelper(node *Node[T, K], indent int) {
+	if node == nil {
+		return
+	}
+
+	// Print the right subtree with indentation
+	tree.printHelper(node.right, indent+4)
+
+	// Print current node
+	fmt.Print(strings.Repeat(" ", indent))
+	fmt.Printf("%v\n", node.key)
+
+	// Print left subtree with indentation
+	tree.printHelper(node.left, indent+4)
+}
+
+func (tree *RedBlackSyncTree[T, K]) CountNodes() int {
+	// CountNodes counts the number of nodes in the tree
+	if tree == nil {
+		return 0
+	}
+	tree.lock()
+	defer tree.unlock()
+	return tree.countNodesRecursive(tree.root)
+}
+
+func (tree *RedBlackSyncTree[T, K]) countNodesRecursive(node *Node[T, K]) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + tree.countNodesRecursive(node.left) + tree.countNodesRecursive(node.right)
+}
+
+func (tree *RedBlackSyncTree[T, K]) Height() int {
+	// Height returns the amount of nodes on the longest path from the root
+	if tree == nil {
+		return 0
+	}
+	tree.lock()
+	defer tree.unlock()
+	return tree.heightRecursive(tree.root)
+}
+
+func (tree *RedBlackSyncTree[T, K]) heightRecursive(node *Node[T, K]) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + max(tree.heightRecursive(node.left), tree.heightRecursive(node.right))
+}
+
+func (tree *RedBlackSyncTree[T, K]) IsValid() bool {
+	// IsValid checks if the tree is a valid binary search tree with valid colors
+	if tree == nil {
+		return true
+	}
+	tree.lock()
+	defer tree.unlock()
+	_, validColors := tree.isValidColorRecursive(tree.root)
+	return tree.isValidBSTRecursive(tree.root, nil, nil) && trees.ValidSizes(tree.root) && validColors && !tree.root.isRed()
+}
+
+func (tree *RedBlackSyncTree[T, K]) isValidBSTRecursive(node *Node[T, K], min *K, max *K) bool {
+	if node == nil {
+		return true
+	}
+	if (min != nil && cmp.Compare(node.key, *min) <= 0) || (max != nil && cmp.Compare(node.key, *max) >= 0) {
+		return false
+	}
+	return tree.isValidBSTRecursive(node.left, min, &node.key) && tree.isValidBSTRecursive(node.right, &node.key, max)
+}
+
+func (tree *RedBlackSyncTree[T, K]) isValidColorRecursive(node *Node[T, K]) (int, bool) {
+	// Returns the amount of black links down to the leaves and whether the red links are valid
+	if node == nil {
+		return 0, true
+	}
+	left, validLeft := tree.isValidColorRecursive(node.left)
+	right, validRight := tree.isValidColorRecursive(node.right)
+	valid := validLeft && validRight && left == right && !node.right.isRed() && !(node.isRed() && node.left.isRed())
+	if !node.red {
+		left++
+	}
+	return left, valid
+}
+
+// Iteration. The pairs are copied under the tree mutex and yielded after it is released,
+// so every iteration sees an atomic snapshot of the tree and the loop body may use the tree freely.
+
+func (tree *RedBlackSyncTree[T, K]) Range(lo, hi K) iter.Seq2[K, T] {
+	return tree.snapshot(&lo, &hi)
+}
+
+func (tree *RedBlackSyncTree[T, K]) All() iter.Seq2[K, T] {
+	return tree.snapshot(nil, nil)
+}
+
+func (tree *RedBlackSyncTree[T, K]) Keys() iter.Seq[K] {
+	return trees.KeysOf(tree.All())
+}
+
+func (tree *RedBlackSyncTree[T, K]) Values() iter.Seq[T] {
+	return trees.ValuesOf(tree.All())
+}
+
+func (tree *RedBlackSyncTree[T, K]) snapshot(lo, hi *K) iter.Seq2[K, T] {
+	return trees.Snapshot(func() []trees.Pair[T, K] {
+		if tree == nil {
+			return nil
+		}
+		tree.lock()
+		defer tree.unlock()
+		return trees.Collect(tree.root, lo, hi)
+	})
+}
+
+// Ordered queries.
+
+func (tree *RedBlackSyncTree[T, K]) Min() (K, T, bool) {
+	return tree.nearest(nil, true, true)
+}
+
+func (tree *RedBlackSyncTree[T, K]) Max() (K, T, bool) {
+	return tree.nearest(nil, true, false)
+}
+
+func (tree *RedBlackSyncTree[T, K]) Floor(key K) (K, T, bool) {
+	return tree.nearest(&key, true, false)
+}
+
+func (tree *RedBlackSyncTree[T, K]) Ceiling(key K) (K, T, bool) {
+	return tree.nearest(&key, true, true)
+}
+
+func (tree *RedBlackSyncTree[T, K]) Predecessor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, false)
+}
+
+func (tree *RedBlackSyncTree[T, K]) Successor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, true)
+}
+
+func (tree *RedBlackSyncTree[T, K]) nearest(bound *K, inclusive, ascending bool) (K, T, bool) {
+	// The closest key beyond bound in the given direction
+	if tree == nil {
+		return *(new(K)), *(new(T)), false
+	}
+	tree.lock()
+	defer tree.unlock()
+	return trees.Nearest(tree.root, bound, inclusive, ascending)
+}
+
+// Order statistics. The subtree sizes are kept by Insert and Remove, so both queries take O(height).
+
+func (tree *RedBlackSyncTree[T, K]) Rank(key K) int {
+	if tree == nil {
+		return 0
+	}
+	tree.lock()
+	defer tree.unlock()
+	return trees.Rank(tree.root, key)
+}
+
+func (tree *RedBlackSyncTree[T, K]) Select(rank int) (K, T, bool) {
+	if tree == nil {
+		return *(new(K)), *(new(T)), false
+	}
+	tree.lock()
+	defer tree.unlock()
+	return trees.Select(tree.root, rank)
+}
+
+// The accessors for the helpers of package trees.
+
+func (node *Node[T, K]) Pair() (K, T) {
+	return node.key, node.value
+}
+
+func (node *Node[T, K]) Left() *Node[T, K] {
+	return node.left
+}
+
+func (node *Node[T, K]) Right() *Node[T, K] {
+	return node.right
+}
+
+func (node *Node[T, K]) Size() int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
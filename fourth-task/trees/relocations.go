@@ -0,0 +1,47 @@
+package trees
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Relocations tracks the two-children removals that move a successor into the removed node.
+// While a successor is moved, a key may be missing from the path that leads to it, so searches
+// that do not hold the locks on their way check that no move overlapped them.
+// Several moves may run at once in different subtrees, so the moves in progress are counted
+// apart from the finished ones: a search is valid when none is in progress at its end
+// and none has finished since its start.
+type Relocations struct {
+	active atomic.Int64  // Moves in progress.
+	epoch  atomic.Uint64 // Finished moves.
+}
+
+func (relocations *Relocations) Begin() {
+	relocations.active.Add(1)
+}
+
+func (relocations *Relocations) End() {
+	// The epoch is raised before the move stops being active,
+	// otherwise a search could see neither the move in progress nor the finished one.
+	relocations.epoch.Add(1)
+	relocations.active.Add(-1)
+}
+
+func (relocations *Relocations) Epoch() uint64 {
+	// Taken before a search. A move in progress at this moment will change the epoch when it ends,
+	// so a search that overlaps it can not be validated.
+	return relocations.epoch.Load()
+}
+
+func (relocations *Relocations) Wait() uint64 {
+	// The same as Epoch, but first lets the moves in progress finish, the search would be wasted otherwise.
+	for relocations.active.Load() != 0 {
+		runtime.Gosched()
+	}
+	return relocations.epoch.Load()
+}
+
+func (relocations *Relocations) Unchanged(epoch uint64) bool {
+	// Whether no move has overlapped the search since the epoch was taken.
+	return relocations.active.Load() == 0 && relocations.epoch.Load() == epoch
+}
@@ -0,0 +1,381 @@
+package seqlockTree
+
+import (
+	"bst/trees"
+	"cmp"
+	"fmt"
+	"iter"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Coarse-grained tree, whose readers do not lock. Writers are serialized by a mutex and make
+// the version odd while they change the tree, readers read the tree optimistically and retry,
+// if the version was odd or has changed meanwhile, so they never write shared memory.
+// All node fields are atomic and the pairs are immutable, so a reader that runs concurrently
+// with a writer sees a consistent pair and a finite path, which it then throws away.
+
+type SeqlockSyncTree[T any, K cmp.Ordered] struct {
+	root    atomic.Pointer[Node[T, K]]
+	mutex   sync.Mutex
+	version atomic.Uint64 // Odd while a writer changes the tree.
+}
+
+type Node[T any, K cmp.Ordered] struct {
+	pair  atomic.Pointer[pair[T, K]]
+	left  atomic.Pointer[Node[T, K]]
+	right atomic.Pointer[Node[T, K]]
+	size  atomic.Int64 // The amount of nodes in the subtree.
+}
+
+type pair[T any, K cmp.Ordered] struct {
+	key   K
+	value T
+}
+
+func FreshSeqlockSyncTree[T any, K cmp.Ordered]() *SeqlockSyncTree[T, K] {
+	return &SeqlockSyncTree[T, K]{}
+}
+
+func freshNode[T any, K cmp.Ordered](key K, value T) *Node[T, K] {
+	node := &Node[T, K]{}
+	node.pair.Store(&pair[T, K]{key: key, value: value})
+	node.size.Store(1)
+	return node
+}
+
+func (node *Node[T, K]) key() K {
+	return node.pair.Load().key
+}
+
+func (tree *SeqlockSyncTree[T, K]) write(change func()) {
+	tree.mutex.Lock()
+	defer tree.mutex.Unlock()
+	tree.version.Add(1)
+	defer tree.version.Add(1)
+	change()
+}
+
+func (tree *SeqlockSyncTree[T, K]) read(attempt func()) {
+	// Runs attempt until no writer has changed the tree during it
+	for {
+		version := tree.version.Load()
+		if version%2 == 1 {
+			runtime.Gosched()
+			continue
+		}
+		attempt()
+		if tree.version.Load() == version {
+			return
+		}
+	}
+}
+
+func (tree *SeqlockSyncTree[T, K]) Insert(key K, value T) {
+	if tree == nil {
+		return
+	}
+	tree.write(func() {
+		if tree.root.Load() == nil {
+			tree.root.Store(freshNode(key, value))
+			return
+		}
+		tree.insertRecursive(tree.root.Load(), key, value)
+	})
+}
+
+func (tree *SeqlockSyncTree[T, K]) insertRecursive(node *Node[T, K], key K, value T) bool {
+	// Returns whether a new node was added
+	inserted := false
+	if cmp.Less(key, node.key()) {
+		if node.left.Load() == nil {
+			node.left.Store(freshNode(key, value))
+			inserted = true
+		} else {
+			inserted = tree.insertRecursive(node.left.Load(), key, value)
+		}
+	} else if cmp.Compare(key, node.key()) == 1 {
+		if node.right.Load() == nil {
+			node.right.Store(freshNode(key, value))
+			inserted = true
+		} else {
+			inserted = tree.insertRecursive(node.right.Load(), key, value)
+		}
+	} else {
+		// Key already exists, update value
+		node.pair.Store(&pair[T, K]{key: key, value: value})
+	}
+	if inserted {
+		node.size.Add(1)
+	}
+	return inserted
+}
+
+func (tree *SeqlockSyncTree[T, K]) Find(key K) (T, bool) {
+	var value T
+	var found bool
+	if tree == nil {
+		return value, false
+	}
+	tree.read(func() {
+		value, found = *(new(T)), false
+		for current := tree.root.Load(); current != nil; {
+			pair := current.pair.Load()
+			comp := cmp.Compare(key, pair.key)
+			if comp == -1 {
+				current = current.left.Load()
+			} else if comp == 1 {
+				current = current.right.Load()
+			} else {
+				value, found = pair.value, true
+				return
+			}
+		}
+	})
+	return value, found
+}
+
+func (tree *SeqlockSyncTree[T, K]) Remove(key K) bool {
+	if tree == nil {
+		return false
+	}
+	var removed bool
+	tree.write(func() {
+		var root *Node[T, K]
+		root, removed = tree.removeRecursive(tree.root.Load(), key)
+		tree.root.Store(root)
+	})
+	return removed
+}
+
+func (tree *SeqlockSyncTree[T, K]) removeRecursive(node *Node[T, K], key K) (*Node[T, K], bool) {
+	if node == nil {
+		return nil, false
+	}
+	var child *Node[T, K]
+	var removed bool
+	comp := cmp.Compare(key, node.key())
+	if comp == -1 {
+		child, removed = tree.removeRecursive(node.left.Load(), key)
+		node.left.Store(child)
+	} else if comp == 1 {
+		child, removed = tree.removeRecursive(node.right.Load(), key)
+		node.right.Store(child)
+	} else {
+		// Node to be deleted found
+		if node.left.Load() == nil {
+			return node.right.Load(), true
+		} else if node.right.Load() == nil {
+			return node.left.Load(), true
+		}
+		// Node to be deleted has two children, the inorder successor takes its place
+		successor := tree.minValueNode(node.right.Load())
+		node.pair.Store(successor.pair.Load())
+		child, _ = tree.removeRecursive(node.right.Load(), successor.key())
+		node.right.Store(child)
+		removed = true
+	}
+	if removed {
+		node.size.Add(-1)
+	}
+	return node, removed
+}
+
+func (tree *SeqlockSyncTree[T, K]) minValueNode(node *Node[T, K]) *Node[T, K] {
+	current := node
+	for current.left.Load() != nil {
+		current = current.left.Load()
+	}
+	return current
+}
+
+func (tree *SeqlockSyncTree[T, K]) Print() {
+	if tree == nil {
+		return
+	}
+	// Printing cannot be retried, so it excludes the writers instead
+	tree.mutex.Lock()
+	defer tree.mutex.Unlock()
+
+	tree.printHelper(tree.root.Load(), 0)
+}
+
+func (tree *SeqlockSyncTree[T, K]) printHelper(node *Node[T, K], indent int) {
+	if node == nil {
+		return
+	}
+
+	// Print the right subtree with indentation
+	tree.printHelper(node.right.Load(), indent+4)
+
+	// Print current node
+	fmt.Print(strings.Repeat(" ", indent))
+	fmt.Printf("%v\n", node.key())
+
+	// Print left subtree with indentation
+	tree.printHelper(node.left.Load(), indent+4)
+}
+
+func (tree *SeqlockSyncTree[T, K]) CountNodes() int {
+	// CountNodes counts the number of nodes in the tree
+	count := 0
+	if tree == nil {
+		return count
+	}
+	tree.read(func() {
+		count = tree.countNodesRecursive(tree.root.Load())
+	})
+	return count
+}
+
+func (tree *SeqlockSyncTree[T, K]) countNodesRecursive(node *Node[T, K]) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + tree.countNodesRecursive(node.left.Load()) + tree.countNodesRecursive(node.right.Load())
+}
+
+func (tree *SeqlockSyncTree[T, K]) IsValid() bool {
+	// IsValid checks if the tree is a valid binary search tree
+	valid := true
+	if tree == nil {
+		return valid
+	}
+	tree.read(func() {
+		valid = tree.isValidBSTRecursive(tree.root.Load(), nil, nil) && trees.ValidSizes(tree.root.Load())
+	})
+	return valid
+}
+
+func (tree *SeqlockSyncTree[T, K]) isValidBSTRecursive(node *Node[T, K], min *K, max *K) bool {
+	if node == nil {
+		return true
+	}
+	key := node.key()
+	if (min != nil && cmp.Compare(key, *min) <= 0) || (max != nil && cmp.Compare(key, *max) >= 0) {
+		return false
+	}
+	return tree.isValidBSTRecursive(node.left.Load(), min, &key) && tree.isValidBSTRecursive(node.right.Load(), &key, max)
+}
+
+// Iteration. The pairs are collected by a single validated read and yielded afterwards,
+// so every iteration sees an atomic snapshot of the tree and the loop body may use the tree freely.
+
+func (tree *SeqlockSyncTree[T, K]) Range(lo, hi K) iter.Seq2[K, T] {
+	return tree.snapshot(&lo, &hi)
+}
+
+func (tree *SeqlockSyncTree[T, K]) All() iter.Seq2[K, T] {
+	return tree.snapshot(nil, nil)
+}
+
+func (tree *SeqlockSyncTree[T, K]) Keys() iter.Seq[K] {
+	return trees.KeysOf(tree.All())
+}
+
+func (tree *SeqlockSyncTree[T, K]) Values() iter.Seq[T] {
+	return trees.ValuesOf(tree.All())
+}
+
+func (tree *SeqlockSyncTree[T, K]) snapshot(lo, hi *K) iter.Seq2[K, T] {
+	return trees.Snapshot(func() []trees.Pair[T, K] {
+		var pairs []trees.Pair[T, K]
+		if tree == nil {
+			return pairs
+		}
+		tree.read(func() {
+			pairs = trees.Collect(tree.root.Load(), lo, hi)
+		})
+		return pairs
+	})
+}
+
+// Ordered queries.
+
+func (tree *SeqlockSyncTree[T, K]) Min() (K, T, bool) {
+	return tree.nearest(nil, true, true)
+}
+
+func (tree *SeqlockSyncTree[T, K]) Max() (K, T, bool) {
+	return tree.nearest(nil, true, false)
+}
+
+func (tree *SeqlockSyncTree[T, K]) Floor(key K) (K, T, bool) {
+	return tree.nearest(&key, true, false)
+}
+
+func (tree *SeqlockSyncTree[T, K]) Ceiling(key K) (K, T, bool) {
+	return tree.nearest(&key, true, true)
+}
+
+func (tree *SeqlockSyncTree[T, K]) Predecessor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, false)
+}
+
+func (tree *SeqlockSyncTree[T, K]) Successor(key K) (K, T, bool) {
+	return tree.nearest(&key, false, true)
+}
+
+func (tree *SeqlockSyncTree[T, K]) nearest(bound *K, inclusive, ascending bool) (K, T, bool) {
+	// The closest key beyond bound in the given direction
+	var key K
+	var value T
+	found := false
+	if tree == nil {
+		return key, value, found
+	}
+	tree.read(func() {
+		key, value, found = trees.Nearest(tree.root.Load(), bound, inclusive, ascending)
+	})
+	return key, value, found
+}
+
+// Order statistics. The subtree sizes are kept by Insert and Remove, so both queries take O(height).
+
+func (tree *SeqlockSyncTree[T, K]) Rank(key K) int {
+	rank := 0
+	if tree == nil {
+		return rank
+	}
+	tree.read(func() {
+		rank = trees.Rank(tree.root.Load(), key)
+	})
+	return rank
+}
+
+func (tree *SeqlockSyncTree[T, K]) Select(rank int) (K, T, bool) {
+	var key K
+	var value T
+	found := false
+	if tree == nil {
+		return key, value, found
+	}
+	tree.read(func() {
+		key, value, found = trees.Select(tree.root.Load(), rank)
+	})
+	return key, value, found
+}
+
+// The accessors for the helpers of package trees. A pair is loaded at once, so its key and value match.
+
+func (node *Node[T, K]) Pair() (K, T) {
+	pair := node.pair.Load()
+	return pair.key, pair.value
+}
+
+func (node *Node[T, K]) Left() *Node[T, K] {
+	return node.left.Load()
+}
+
+func (node *Node[T, K]) Right() *Node[T, K] {
+	return node.right.Load()
+}
+
+func (node *Node[T, K]) Size() int {
+	if node == nil {
+		return 0
+	}
+	return int(node.size.Load())
+}
@@ -1,6 +1,9 @@
 package trees
 
-import "cmp"
+import (
+	"cmp"
+	"iter"
+)
 
 type BinarySearchTree[T any, K cmp.Ordered] interface {
 	Find(K) (T, bool)
@@ -9,4 +12,241 @@ type BinarySearchTree[T any, K cmp.Ordered] interface {
 	CountNodes() int
 	IsValid() bool
 	Print()
+	Range(lo, hi K) iter.Seq2[K, T] // Pairs with lo <= key <= hi in increasing order of keys.
+	All() iter.Seq2[K, T]
+	Keys() iter.Seq[K]
+	Values() iter.Seq[T]
+	Min() (K, T, bool)
+	Max() (K, T, bool)
+	Floor(K) (K, T, bool)       // The largest key <= the given one.
+	Ceiling(K) (K, T, bool)     // The smallest key >= the given one.
+	Predecessor(K) (K, T, bool) // The largest key < the given one.
+	Successor(K) (K, T, bool)   // The smallest key > the given one.
+}
+
+type OrderStatisticTree[T any, K cmp.Ordered] interface {
+	BinarySearchTree[T, K]
+	Rank(K) int              // The amount of keys < the given one.
+	Select(int) (K, T, bool) // The pair with the given zero-based rank.
+}
+
+// Helpers for trees that iterate with an in-order cursor.
+
+// Seek starts an in-order walk at the smallest key above bound (or equal to it, if inclusive),
+// a nil bound means the smallest key of the tree.
+type Seek[T any, K cmp.Ordered] func(bound *K, inclusive bool) Step[T, K]
+
+// Step returns the next pair of the walk. The walk is lost, if a concurrent change may have moved
+// keys out of its way, then it is started again past the last yielded key.
+type Step[T any, K cmp.Ordered] func() (key K, value T, found, lost bool)
+
+func Beyond[K cmp.Ordered](key K, bound *K, inclusive, ascending bool) bool {
+	// Whether key may answer a query for the closest key above bound (below it, if not ascending),
+	// a nil bound is passed by any key.
+	if bound == nil || (inclusive && key == *bound) {
+		return true
+	}
+	if ascending {
+		return cmp.Less(*bound, key)
+	}
+	return cmp.Less(key, *bound)
+}
+
+func Ascend[T any, K cmp.Ordered](seek Seek[T, K], lo, hi *K) iter.Seq2[K, T] {
+	// Pairs between the bounds, a nil bound is no bound. The steps hold no locks between each other,
+	// so the loop body may update the tree. Keys that are not above the last yielded one are skipped,
+	// so the order stays strict even if the cursor comes back to a part of the tree it has passed.
+	return func(yield func(K, T) bool) {
+		step := seek(lo, true)
+		var last K
+		started := false
+		for {
+			key, value, found, lost := step()
+			if lost {
+				if started {
+					step = seek(&last, false)
+				} else {
+					step = seek(lo, true)
+				}
+				continue
+			}
+			if !found || (hi != nil && cmp.Less(*hi, key)) {
+				return
+			}
+			if started && !cmp.Less(last, key) {
+				continue
+			}
+			if !yield(key, value) {
+				return
+			}
+			last, started = key, true
+		}
+	}
+}
+
+func KeysOf[T any, K cmp.Ordered](pairs iter.Seq2[K, T]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range pairs {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+func ValuesOf[T any, K cmp.Ordered](pairs iter.Seq2[K, T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range pairs {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+func Empty[T any, K cmp.Ordered]() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {}
+}
+
+// Helpers for trees that are read under a lock or by a validated read, so that no node changes meanwhile.
+
+// Node gives the helpers access to a node of such a tree, a nil node is the zero N.
+type Node[N comparable, T any, K cmp.Ordered] interface {
+	comparable
+	Pair() (K, T)
+	Left() N
+	Right() N
+	Size() int // The amount of nodes in the subtree.
+}
+
+type Pair[T any, K cmp.Ordered] struct {
+	Key   K
+	Value T
+}
+
+func Snapshot[T any, K cmp.Ordered](collect func() []Pair[T, K]) iter.Seq2[K, T] {
+	// The pairs are collected when the iteration starts and yielded afterwards,
+	// so the loop body may use the tree freely.
+	return func(yield func(K, T) bool) {
+		for _, pair := range collect() {
+			if !yield(pair.Key, pair.Value) {
+				return
+			}
+		}
+	}
+}
+
+func Collect[N Node[N, T, K], T any, K cmp.Ordered](root N, lo, hi *K) []Pair[T, K] {
+	// The pairs between the bounds in increasing order of keys, a nil bound is no bound.
+	var pairs []Pair[T, K]
+	collectRecursive(root, lo, hi, &pairs)
+	return pairs
+}
+
+func collectRecursive[N Node[N, T, K], T any, K cmp.Ordered](node N, lo, hi *K, pairs *[]Pair[T, K]) {
+	// In-order walk that skips the subtrees outside the bounds.
+	var null N
+	if node == null {
+		return
+	}
+	key, value := node.Pair()
+	aboveLo := lo == nil || cmp.Compare(key, *lo) >= 0
+	belowHi := hi == nil || cmp.Compare(key, *hi) <= 0
+	if aboveLo {
+		collectRecursive(node.Left(), lo, hi, pairs)
+	}
+	if aboveLo && belowHi {
+		*pairs = append(*pairs, Pair[T, K]{Key: key, Value: value})
+	}
+	if belowHi {
+		collectRecursive(node.Right(), lo, hi, pairs)
+	}
+}
+
+func Nearest[N Node[N, T, K], T any, K cmp.Ordered](root N, bound *K, inclusive, ascending bool) (K, T, bool) {
+	// The closest key beyond bound in the given direction.
+	var null N
+	var key K
+	var value T
+	found := false
+	for current := root; current != null; {
+		currentKey, currentValue := current.Pair()
+		if Beyond(currentKey, bound, inclusive, ascending) {
+			// The current key fits, but a closer one may be on the near side
+			key, value, found = currentKey, currentValue, true
+			current = near(current, ascending)
+		} else {
+			current = near(current, !ascending)
+		}
+	}
+	return key, value, found
+}
+
+func near[N Node[N, T, K], T any, K cmp.Ordered](node N, ascending bool) N {
+	// The child with the keys closer to the bound, the other one is far
+	if ascending {
+		return node.Left()
+	}
+	return node.Right()
+}
+
+func Rank[N Node[N, T, K], T any, K cmp.Ordered](root N, key K) int {
+	// The amount of keys < the given one, O(height) with the subtree sizes.
+	var null N
+	rank := 0
+	for current := root; current != null; {
+		if currentKey, _ := current.Pair(); cmp.Less(currentKey, key) {
+			rank += 1 + sizeOf(current.Left())
+			current = current.Right()
+		} else {
+			current = current.Left()
+		}
+	}
+	return rank
+}
+
+func Select[N Node[N, T, K], T any, K cmp.Ordered](root N, rank int) (K, T, bool) {
+	// The pair with the given zero-based rank, O(height) with the subtree sizes.
+	var null N
+	if rank < 0 || rank >= sizeOf(root) {
+		return *(new(K)), *(new(T)), false
+	}
+	for current := root; current != null; {
+		left := sizeOf(current.Left())
+		if rank < left {
+			current = current.Left()
+		} else if rank > left {
+			rank -= left + 1
+			current = current.Right()
+		} else {
+			key, value := current.Pair()
+			return key, value, true
+		}
+	}
+	return *(new(K)), *(new(T)), false
+}
+
+func sizeOf[N Node[N, T, K], T any, K cmp.Ordered](node N) int {
+	var null N
+	if node == null {
+		return 0
+	}
+	return node.Size()
+}
+
+func ValidSizes[N Node[N, T, K], T any, K cmp.Ordered](root N) bool {
+	// Whether the stored size of every node matches the real size of its subtree.
+	_, valid := validSizesRecursive(root)
+	return valid
+}
+
+func validSizesRecursive[N Node[N, T, K], T any, K cmp.Ordered](node N) (int, bool) {
+	// Returns the real size of the subtree and whether all the stored sizes match
+	var null N
+	if node == null {
+		return 0, true
+	}
+	left, validLeft := validSizesRecursive(node.Left())
+	right, validRight := validSizesRecursive(node.Right())
+	return 1 + left + right, validLeft && validRight && node.Size() == 1+left+right
 }
@@ -0,0 +1,133 @@
+package treetest
+
+import (
+	"cmp"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// Random concurrent histories of insertions, removals and searches are recorded and checked
+// for linearizability: there must be an order of the operations that respects their real-time
+// order and is valid for a sequential map.
+
+const (
+	insertOperation = iota
+	removeOperation
+	findOperation
+)
+
+const linearizabilityGorutinesAmount = 3
+const linearizabilityOperationsAmount = 4
+const linearizabilityKeysAmount = 7
+
+type operation[T comparable] struct {
+	kind    int
+	key     int
+	value   int // The inserted value index, values are unique within a history.
+	found   T   // The value returned by a search.
+	ok      bool
+	call    int64
+	respond int64
+}
+
+// The value index stored under every key, -1 for absent keys.
+type model [linearizabilityKeysAmount]int
+
+func prefilledModel() model {
+	// The tree is filled with the keys of a complete tree beforehand,
+	// so that removals take the two-children paths as well.
+	var state model
+	for i := range state {
+		state[i] = i
+	}
+	return state
+}
+
+func recordHistory[T comparable, K cmp.Ordered](factory Factory[T, K], random *rand.Rand) []operation[T] {
+	// Every goroutine runs a few random operations and records when each of them started and ended.
+	tree := factory.New()
+	for _, i := range []int{3, 1, 5, 0, 2, 4, 6} {
+		tree.Insert(factory.Key(i), factory.Value(i))
+	}
+	clock := atomic.Int64{}
+	histories := make([][]operation[T], linearizabilityGorutinesAmount)
+	for g := range histories {
+		for i := 0; i < linearizabilityOperationsAmount; i++ {
+			histories[g] = append(histories[g], operation[T]{
+				kind:  random.Intn(3),
+				key:   random.Intn(linearizabilityKeysAmount),
+				value: linearizabilityKeysAmount + g*linearizabilityOperationsAmount + i,
+			})
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(linearizabilityGorutinesAmount)
+	for g := 0; g < linearizabilityGorutinesAmount; g++ {
+		go func() {
+			defer wg.Done()
+			for i := range histories[g] {
+				operation := &histories[g][i]
+				operation.call = clock.Add(1)
+				switch operation.kind {
+				case insertOperation:
+					tree.Insert(factory.Key(operation.key), factory.Value(operation.value))
+				case removeOperation:
+					operation.ok = tree.Remove(factory.Key(operation.key))
+				default:
+					operation.found, operation.ok = tree.Find(factory.Key(operation.key))
+				}
+				operation.respond = clock.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var history []operation[T]
+	for _, operations := range histories {
+		history = append(history, operations...)
+	}
+	return history
+}
+
+func apply[T comparable, K cmp.Ordered](factory Factory[T, K], state model, operation operation[T]) (model, bool) {
+	// Returns the next state and whether the recorded result agrees with the sequential map.
+	present := state[operation.key] >= 0
+	switch operation.kind {
+	case insertOperation:
+		state[operation.key] = operation.value
+		return state, true
+	case removeOperation:
+		state[operation.key] = -1
+		return state, operation.ok == present
+	default:
+		return state, operation.ok == present && (!present || operation.found == factory.Value(state[operation.key]))
+	}
+}
+
+func linearizable[T comparable, K cmp.Ordered](factory Factory[T, K], history []operation[T], state model) bool {
+	// Wing and Gong search: any operation that started before every other pending one ended
+	// may be linearized next.
+	if len(history) == 0 {
+		return true
+	}
+	earliestRespond := history[0].respond
+	for _, candidate := range history {
+		earliestRespond = min(earliestRespond, candidate.respond)
+	}
+	for i, candidate := range history {
+		if candidate.call > earliestRespond {
+			continue
+		}
+		next, ok := apply(factory, state, candidate)
+		if !ok {
+			continue
+		}
+		rest := append(append([]operation[T](nil), history[:i]...), history[i+1:]...)
+		if linearizable(factory, rest, next) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,929 @@
+package treetest
+
+import (
+	"bst/trees"
+	"cmp"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// Conformance tests and benchmarks for any implementation of trees.BinarySearchTree.
+// A third-party tree only has to provide a Factory:
+//
+//	func TestMyTree(t *testing.T) {
+//		treetest.Run(t, treetest.Ints(func() trees.BinarySearchTree[int, int] { return FreshMyTree[int, int]() }))
+//	}
+
+type Factory[T comparable, K cmp.Ordered] struct {
+	New   func() trees.BinarySearchTree[T, K] // Returns a new empty tree.
+	Key   func(int) K                         // Must be strictly increasing for non-negative arguments.
+	Value func(int) T                         // The value stored under Key(i).
+}
+
+func Ints(newTree func() trees.BinarySearchTree[int, int]) Factory[int, int] {
+	// Factory for trees of integers, where the key i holds the value i*i.
+	return Factory[int, int]{
+		New:   newTree,
+		Key:   func(i int) int { return i },
+		Value: func(i int) int { return i * i },
+	}
+}
+
+func Run[T comparable, K cmp.Ordered](t *testing.T, factory Factory[T, K]) {
+	// All the cases, the tree must be safe for concurrent use.
+	key, value := factory.Key, factory.Value
+
+	t.Run("Test insert", func(t *testing.T) {
+		/* This test runs 100 goroutines, each of which performs an insert.
+		And then it checks that all values have been inserted and the tree
+		structure is not broken. */
+		const nodesAmount = 100
+
+		tree := factory.New()
+		wg := sync.WaitGroup{}
+		wg.Add(nodesAmount)
+		for i := 0; i < nodesAmount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				tree.Insert(key(i), value(i))
+			}(i)
+		}
+		wg.Wait()
+
+		if !tree.IsValid() {
+			t.Errorf("Error: tree is not valid.")
+		}
+		if tree.CountNodes() != nodesAmount {
+			t.Errorf("Error: the tree contains %d nodes, although %d were expected", tree.CountNodes(), nodesAmount)
+		}
+	})
+
+	t.Run("Test find after insert | First", func(t *testing.T) {
+		/* This test starts 50 insert goroutines, waits for them to execute,
+		and then runs 50 search goroutines, which check that all goroutines have been
+		inserted before.
+		Then the tree structure and the number of inserted elements are checked. */
+		tree := factory.New()
+
+		if _, flag := tree.Find(key(1)); flag {
+			t.Errorf("The find function found a non-existent node in the tree")
+		}
+		const nodesAmount = 50
+		wg := sync.WaitGroup{}
+		wg.Add(nodesAmount)
+		for i := 0; i < nodesAmount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				tree.Insert(key(i), value(i))
+			}(i)
+		}
+		wg.Wait()
+
+		wg.Add(nodesAmount)
+		for i := 0; i < nodesAmount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				found, flag := tree.Find(key(i))
+				if !flag {
+					t.Errorf("The find function did not find an existing node.")
+				}
+				if found != value(i) {
+					t.Errorf("The node found contains the value %v when the value %v was expected.", found, value(i))
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if !tree.IsValid() {
+			t.Errorf("The find function broke the tree")
+		}
+		if tree.CountNodes() != nodesAmount {
+			t.Errorf("The find function change the tree")
+		}
+	})
+
+	t.Run("Test find after insert | Second", func(t *testing.T) {
+		/* 300 goroutines are launched, each of which performs the insertion and
+		immediately checks for the presence of the inserted element in the tree.
+		The test checks the correctness of the find function itself. */
+		tree := factory.New()
+
+		const nodesAmount = 300
+		wg := sync.WaitGroup{}
+		wg.Add(nodesAmount)
+		for i := 0; i < nodesAmount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				tree.Insert(key(i), value(i))
+				found, flag := tree.Find(key(i))
+				if !flag {
+					t.Errorf("The find function did not find an existing node.")
+				}
+				if found != value(i) {
+					t.Errorf("The node found contains the value %v when the value %v was expected.", found, value(i))
+				}
+			}(i)
+		}
+		wg.Wait()
+		if !tree.IsValid() {
+			t.Errorf("The find function broke the tree")
+		}
+		if tree.CountNodes() != nodesAmount {
+			t.Errorf("The find function change the tree")
+		}
+	})
+
+	t.Run("Test find and insert parallel", func(t *testing.T) {
+		/* The test runs 600 goroutines - 300 for insertion and 300 for search.
+		Then it checks that the tree was built correctly and that it actually has 300 nodes.
+		The test essentially checks that operations do not conflict with each other and that there
+		is no data race. */
+		tree := factory.New()
+
+		const nodesAmount = 300
+		wg := sync.WaitGroup{}
+		wg.Add(nodesAmount * 2)
+		for i := 0; i < nodesAmount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				tree.Insert(key(i), value(i))
+			}(i)
+			go func(i int) {
+				defer wg.Done()
+				found, flag := tree.Find(key(i))
+				if flag && found != value(i) {
+					t.Errorf("The node found contains the value %v when the value %v was expected.", found, value(i))
+				}
+			}(i)
+		}
+		wg.Wait()
+		if !tree.IsValid() {
+			t.Errorf("The find function broke the tree")
+		}
+		if tree.CountNodes() != nodesAmount {
+			t.Errorf("The find function change the tree")
+		}
+	})
+
+	t.Run("Test remove | First", func(t *testing.T) {
+		/* The test first inserts 500 elements into the tree,
+		and then runs 500 goroutines to remove these elements.
+		Then it checks that the tree is empty. Essentially,
+		the test verifies that remove works correctly and is thread safe. */
+		const nodesAmount = 500
+
+		tree := factory.New()
+
+		wg := sync.WaitGroup{}
+		wg.Add(nodesAmount)
+		for i := 0; i < nodesAmount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				tree.Insert(key(i), value(i))
+			}(i)
+		}
+		wg.Wait()
+
+		wg.Add(nodesAmount)
+		for i := 0; i < nodesAmount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				if !tree.Remove(key(i)) {
+					t.Errorf("Failed to remove a node that was previously added.")
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if !tree.IsValid() || tree.CountNodes() != 0 {
+			t.Errorf("The tree was expected to be empty.")
+		}
+	})
+
+	t.Run("Test remove | Second", func(t *testing.T) {
+		/* The test runs 1000 goroutines - 500 for insertion and
+		500 for deletion at the same time. Then it checks that the tree
+		structure is not broken. The test essentially checks that operations do not
+		conflict with each other and that data races do not occur. */
+		const nodesAmount = 500
+
+		tree := factory.New()
+
+		wg := sync.WaitGroup{}
+		wg.Add(nodesAmount * 2)
+		for i := 0; i < nodesAmount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				tree.Insert(key(i), value(i))
+			}(i)
+			go func(i int) {
+				defer wg.Done()
+				tree.Remove(key(i))
+			}(i)
+		}
+		wg.Wait()
+		if !tree.IsValid() {
+			t.Errorf("Remove broke tree.")
+		}
+	})
+
+	t.Run("Test remove | Neighbours", func(t *testing.T) {
+		/* One goroutine removes the even keys, while others insert the odd ones,
+		which are often attached to the nodes that are being removed at the moment.
+		Afterwards exactly the odd keys must remain. */
+		const nodesAmount = 2000
+		const gorutinesAmount = 4
+
+		tree := factory.New()
+		for _, i := range rand.Perm(nodesAmount / 2) {
+			tree.Insert(key(2*i), value(2*i))
+		}
+
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount + 1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < nodesAmount; i += 2 {
+				tree.Remove(key(i))
+			}
+		}()
+		for g := 0; g < gorutinesAmount; g++ {
+			go func(g int) {
+				defer wg.Done()
+				for i := 2*g + 1; i < nodesAmount; i += 2 * gorutinesAmount {
+					tree.Insert(key(i), value(i))
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		if !tree.IsValid() {
+			t.Errorf("Remove broke tree.")
+		}
+		for i := 1; i < nodesAmount; i += 2 {
+			if _, flag := tree.Find(key(i)); !flag {
+				t.Fatalf("The key %d inserted next to a removed node was lost.", i)
+			}
+		}
+		if tree.CountNodes() != nodesAmount/2 {
+			t.Errorf("Error: the tree contains %d nodes, although %d were expected", tree.CountNodes(), nodesAmount/2)
+		}
+	})
+
+	t.Run("Test find during two-children removals", func(t *testing.T) {
+		/* The keys are inserted in random order and the even ones are removed by several goroutines,
+		so most removed nodes are high in the tree and have two children: their successors,
+		often odd keys, are moved up into their place, and moves in different subtrees overlap.
+		Meanwhile searches for the odd keys, which are never removed, must always find them
+		with their values. The goroutines run on several threads even when the test is not
+		started with -cpu, so that a move can be preempted in the middle. */
+		const nodesAmount = 4000
+		const removersAmount = 8
+		const searchersAmount = 4
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(max(runtime.GOMAXPROCS(0), 4)))
+
+		tree := factory.New()
+		order := rand.Perm(nodesAmount)
+		for _, i := range order {
+			tree.Insert(key(i), value(i))
+		}
+
+		removers := sync.WaitGroup{}
+		removers.Add(removersAmount)
+		done := make(chan struct{})
+		for g := 0; g < removersAmount; g++ {
+			go func(g int) {
+				defer removers.Done()
+				for j := g; j < nodesAmount; j += removersAmount {
+					if order[j]%2 == 0 && !tree.Remove(key(order[j])) {
+						t.Errorf("Failed to remove a node that was previously added.")
+					}
+				}
+			}(g)
+		}
+		go func() {
+			removers.Wait()
+			close(done)
+		}()
+
+		searchers := sync.WaitGroup{}
+		searchers.Add(searchersAmount)
+		for g := 0; g < searchersAmount; g++ {
+			go func() {
+				defer searchers.Done()
+				for {
+					for i := 1 + 2*g; i < nodesAmount; i += 2 * searchersAmount {
+						if found, flag := tree.Find(key(i)); !flag || found != value(i) {
+							t.Errorf("The find function did not find the key %d during removals.", i)
+							return
+						}
+					}
+					select {
+					case <-done:
+						return
+					default:
+					}
+				}
+			}()
+		}
+		searchers.Wait()
+
+		if !tree.IsValid() || tree.CountNodes() != nodesAmount/2 {
+			t.Errorf("Error: the tree is not valid or does not contain %d nodes after the removals.", nodesAmount/2)
+		}
+	})
+
+	t.Run("Test isValid", func(t *testing.T) {
+		/* The test builds a valid tree and
+		then checks that the isValid function works correctly. */
+		tree := factory.New()
+		if !tree.IsValid() {
+			t.Errorf("Error: function IsValid is not working correctly")
+		}
+		for _, i := range []int{20, 5, 22, 21, 3, 4, 6, 7} {
+			tree.Insert(key(i), value(i))
+		}
+		if !tree.IsValid() {
+			t.Errorf("Error: function IsValid is not working correctly")
+		}
+	})
+
+	t.Run("Test empty tree", func(t *testing.T) {
+		tree := factory.New()
+		if _, flag := tree.Find(key(0)); flag {
+			t.Errorf("The find function found a node in an empty tree.")
+		}
+		if tree.Remove(key(0)) {
+			t.Errorf("The remove function removed a node from an empty tree.")
+		}
+		if tree.CountNodes() != 0 {
+			t.Errorf("Error: the empty tree contains %d nodes.", tree.CountNodes())
+		}
+		if !tree.IsValid() {
+			t.Errorf("Error: the empty tree is not valid.")
+		}
+	})
+
+	t.Run("Test duplicate keys", func(t *testing.T) {
+		/* Inserting an existing key replaces its value,
+		removing a key twice succeeds only once. */
+		tree := factory.New()
+		tree.Insert(key(1), value(1))
+		tree.Insert(key(1), value(2))
+		if tree.CountNodes() != 1 {
+			t.Errorf("Error: the tree contains %d nodes, although 1 was expected", tree.CountNodes())
+		}
+		if found, _ := tree.Find(key(1)); found != value(2) {
+			t.Errorf("The node found contains the value %v when the value %v was expected.", found, value(2))
+		}
+		if !tree.Remove(key(1)) {
+			t.Errorf("Failed to remove a node that was previously added.")
+		}
+		if tree.Remove(key(1)) {
+			t.Errorf("The remove function removed the same node twice.")
+		}
+	})
+
+	t.Run("Test duplicate keys parallel", func(t *testing.T) {
+		/* 100 goroutines insert the same key,
+		the tree must end up with a single node. */
+		const gorutinesAmount = 100
+		tree := factory.New()
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for i := 0; i < gorutinesAmount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				tree.Insert(key(1), value(i))
+			}(i)
+		}
+		wg.Wait()
+		if tree.CountNodes() != 1 {
+			t.Errorf("Error: the tree contains %d nodes, although 1 was expected", tree.CountNodes())
+		}
+		if !tree.IsValid() {
+			t.Errorf("Error: tree is not valid.")
+		}
+	})
+
+	t.Run("Test linearizability", func(t *testing.T) {
+		/* Three goroutines run a few random insertions, removals and searches on a small
+		tree, every recorded history must be explained by some sequential order. */
+		const historiesAmount = 2000
+		for i := 0; i < historiesAmount; i++ {
+			history := recordHistory(factory, rand.New(rand.NewSource(int64(i))))
+			if !linearizable(factory, history, prefilledModel()) {
+				t.Fatalf("History %d is not linearizable: %v", i, history)
+			}
+		}
+	})
+
+	t.Run("Test nil tree", func(t *testing.T) {
+		/* A nil pointer of the tree type behaves like an empty tree
+		that ignores insertions. */
+		treeType := reflect.TypeOf(factory.New())
+		if treeType == nil || treeType.Kind() != reflect.Pointer {
+			t.Skip("The tree is not a pointer.")
+		}
+		tree := reflect.Zero(treeType).Interface().(trees.BinarySearchTree[T, K])
+
+		tree.Insert(key(1), value(1))
+		if _, flag := tree.Find(key(1)); flag {
+			t.Errorf("The find function found a node in a nil tree.")
+		}
+		if tree.Remove(key(1)) {
+			t.Errorf("The remove function removed a node from a nil tree.")
+		}
+		if tree.CountNodes() != 0 {
+			t.Errorf("Error: the nil tree contains %d nodes.", tree.CountNodes())
+		}
+		if !tree.IsValid() {
+			t.Errorf("Error: the nil tree is not valid.")
+		}
+		tree.Print()
+		for range tree.All() {
+			t.Errorf("Error: the nil tree yielded a node.")
+		}
+		if _, _, found := tree.Floor(key(1)); found {
+			t.Errorf("The floor function found a node in a nil tree.")
+		}
+	})
+
+	t.Run("Test range", func(t *testing.T) {
+		/* Random keys are inserted and some of them are removed,
+		every range must match the sorted remaining keys. */
+		const nodesAmount = 1000
+		tree := factory.New()
+		present := make([]bool, nodesAmount)
+		for _, i := range rand.Perm(nodesAmount) {
+			if i%3 != 0 {
+				tree.Insert(key(i), value(i))
+				present[i] = true
+			}
+		}
+		for i := 1; i < nodesAmount; i += 5 {
+			tree.Remove(key(i))
+			present[i] = false
+		}
+
+		for _, bounds := range [][2]int{{0, nodesAmount - 1}, {10, 20}, {3, 3}, {4, 4}, {500, 499}, {nodesAmount - 5, nodesAmount - 1}} {
+			lo, hi := bounds[0], bounds[1]
+			expected := lo
+			for k, v := range tree.Range(key(lo), key(hi)) {
+				for expected <= hi && !present[expected] {
+					expected++
+				}
+				if expected > hi || k != key(expected) || v != value(expected) {
+					t.Fatalf("Range [%d, %d] yielded the key %v with the value %v instead of the key %d.", lo, hi, k, v, expected)
+				}
+				expected++
+			}
+			for expected <= hi && !present[expected] {
+				expected++
+			}
+			if expected <= hi {
+				t.Errorf("Range [%d, %d] did not yield the key %d.", lo, hi, expected)
+			}
+		}
+
+		count := 0
+		for k := range tree.Keys() {
+			if count > 0 && k == key(nodesAmount/2) {
+				break
+			}
+			count++
+		}
+		if count == tree.CountNodes() {
+			t.Errorf("Error: the iteration did not stop after break.")
+		}
+		count = 0
+		for range tree.Values() {
+			count++
+		}
+		if count != tree.CountNodes() {
+			t.Errorf("Error: Values yielded %d values, although %d were expected", count, tree.CountNodes())
+		}
+		for range factory.New().All() {
+			t.Errorf("Error: the empty tree yielded a node.")
+		}
+	})
+
+	t.Run("Test range parallel", func(t *testing.T) {
+		/* Even keys are inserted beforehand and stay in the tree, while goroutines insert
+		and remove odd keys: every iteration must yield increasing keys with their values
+		and must not miss any even key. */
+		const nodesAmount = 1000
+		const gorutinesAmount = 4
+		tree := factory.New()
+		for _, i := range rand.Perm(nodesAmount) {
+			if i%2 == 0 {
+				tree.Insert(key(i), value(i))
+			}
+		}
+
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for g := 0; g < gorutinesAmount; g++ {
+			go func(g int) {
+				defer wg.Done()
+				for i := 2*g + 1; i < nodesAmount; i += 2 * gorutinesAmount {
+					tree.Insert(key(i), value(i))
+				}
+				for i := 2*g + 1; i < nodesAmount; i += 2 * gorutinesAmount {
+					tree.Remove(key(i))
+				}
+			}(g)
+		}
+
+		for iteration := 0; iteration < 10; iteration++ {
+			next := 0 // The smallest key that may be yielded next.
+			for k, v := range tree.All() {
+				for next < nodesAmount && key(next) != k {
+					if next%2 == 0 {
+						t.Fatalf("The iteration missed the key %d.", next)
+					}
+					next++
+				}
+				if next == nodesAmount || v != value(next) {
+					t.Fatalf("The iteration yielded the key %v with the value %v out of order.", k, v)
+				}
+				next++
+			}
+			if next < nodesAmount-1 {
+				t.Fatalf("The iteration stopped before the key %d.", next)
+			}
+		}
+		wg.Wait()
+	})
+
+	t.Run("Test update during iteration", func(t *testing.T) {
+		/* The loop body removes every yielded key and puts back the even ones,
+		which are behind the iteration then: no lock may be held while the body runs,
+		and the remaining keys must still be yielded in order. */
+		const nodesAmount = 1000
+		tree := factory.New()
+		for _, i := range rand.Perm(nodesAmount) {
+			tree.Insert(key(i), value(i))
+		}
+
+		expected := 0
+		for k, v := range tree.All() {
+			if expected == nodesAmount || k != key(expected) || v != value(expected) {
+				t.Fatalf("The iteration yielded the key %v with the value %v instead of the key %d.", k, v, expected)
+			}
+			tree.Remove(k)
+			if expected%2 == 0 {
+				tree.Insert(k, v)
+			}
+			expected++
+		}
+		if expected != nodesAmount {
+			t.Errorf("The iteration stopped before the key %d.", expected)
+		}
+		if !tree.IsValid() || tree.CountNodes() != nodesAmount/2 {
+			t.Errorf("Error: the tree was broken by the updates during the iteration.")
+		}
+	})
+
+	t.Run("Test ordered queries", func(t *testing.T) {
+		/* Every third key is inserted, the queries are checked
+		for all keys, including the ones out of the tree range. */
+		const nodesAmount = 300
+		tree := factory.New()
+		if _, _, found := tree.Min(); found {
+			t.Errorf("The min function found a node in an empty tree.")
+		}
+		if _, _, found := tree.Successor(key(0)); found {
+			t.Errorf("The successor function found a node in an empty tree.")
+		}
+		for _, i := range rand.Perm(nodesAmount) {
+			if i%3 == 1 {
+				tree.Insert(key(i), value(i))
+			}
+		}
+
+		check := func(name string, k K, v T, found bool, expected int) {
+			t.Helper()
+			if expected < 0 || expected >= nodesAmount {
+				if found {
+					t.Errorf("The %s function found the key %v, although no key was expected.", name, k)
+				}
+				return
+			}
+			if !found || k != key(expected) || v != value(expected) {
+				t.Errorf("The %s function found the key %v with the value %v when the key %d was expected.", name, k, v, expected)
+			}
+		}
+		k, v, found := tree.Min()
+		check("min", k, v, found, 1)
+		k, v, found = tree.Max()
+		check("max", k, v, found, nodesAmount-2)
+		for i := 0; i < nodesAmount; i++ {
+			// The closest inserted keys: i itself, if it was inserted, or the neighbours.
+			below, above := i-(i+2)%3, i+(4-i%3)%3
+			k, v, found = tree.Floor(key(i))
+			check("floor", k, v, found, below)
+			k, v, found = tree.Ceiling(key(i))
+			check("ceiling", k, v, found, above)
+			if i%3 == 1 {
+				below, above = i-3, i+3
+			}
+			k, v, found = tree.Predecessor(key(i))
+			check("predecessor", k, v, found, below)
+			k, v, found = tree.Successor(key(i))
+			check("successor", k, v, found, above)
+		}
+	})
+
+	t.Run("Test ordered queries parallel", func(t *testing.T) {
+		/* Even keys stay in the tree, while goroutines insert and remove odd keys:
+		the answer for any key is either the closest even key or the odd key next to it. */
+		const nodesAmount = 1000
+		const gorutinesAmount = 4
+		tree := factory.New()
+		for _, i := range rand.Perm(nodesAmount) {
+			if i%2 == 0 {
+				tree.Insert(key(i), value(i))
+			}
+		}
+
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for g := 0; g < gorutinesAmount; g++ {
+			go func(g int) {
+				defer wg.Done()
+				for i := 2*g + 1; i < nodesAmount; i += 2 * gorutinesAmount {
+					tree.Insert(key(i), value(i))
+				}
+				for i := 2*g + 1; i < nodesAmount; i += 2 * gorutinesAmount {
+					tree.Remove(key(i))
+				}
+			}(g)
+		}
+
+		check := func(name string, k K, v T, found bool, expected ...int) {
+			t.Helper()
+			for _, i := range expected {
+				if found && k == key(i) && v == value(i) {
+					return
+				}
+			}
+			t.Fatalf("The %s function found the key %v with the value %v when one of the keys %v was expected.", name, k, v, expected)
+		}
+		for i := 2; i < nodesAmount-2; i += 2 {
+			k, v, found := tree.Successor(key(i))
+			check("successor", k, v, found, i+1, i+2)
+			k, v, found = tree.Predecessor(key(i))
+			check("predecessor", k, v, found, i-1, i-2)
+			k, v, found = tree.Ceiling(key(i + 1))
+			check("ceiling", k, v, found, i+1, i+2)
+			k, v, found = tree.Floor(key(i + 1))
+			check("floor", k, v, found, i+1, i)
+			k, v, found = tree.Min()
+			check("min", k, v, found, 0)
+			k, v, found = tree.Max()
+			check("max", k, v, found, nodesAmount-1, nodesAmount-2)
+		}
+		wg.Wait()
+	})
+
+	orderStatistic := func(t *testing.T) trees.OrderStatisticTree[T, K] {
+		tree, ok := factory.New().(trees.OrderStatisticTree[T, K])
+		if !ok {
+			t.Skip("The tree does not support order statistics.")
+		}
+		return tree
+	}
+
+	t.Run("Test rank and select", func(t *testing.T) {
+		/* Every second key is inserted, the ranks and the selected
+		keys must match their positions among the inserted keys. */
+		const nodesAmount = 500
+		tree := orderStatistic(t)
+		for _, i := range rand.Perm(nodesAmount) {
+			if i%2 == 1 {
+				tree.Insert(key(i), value(i))
+			}
+		}
+		for i := 0; i < nodesAmount; i++ {
+			if rank := tree.Rank(key(i)); rank != i/2 {
+				t.Errorf("Received rank %d != expected rank %d of the key %d", rank, i/2, i)
+			}
+		}
+		for rank := 0; rank < nodesAmount/2; rank++ {
+			if k, v, found := tree.Select(rank); !found || k != key(2*rank+1) || v != value(2*rank+1) {
+				t.Errorf("The select function found the key %v for the rank %d when the key %d was expected.", k, rank, 2*rank+1)
+			}
+		}
+		for _, rank := range []int{-1, nodesAmount / 2} {
+			if _, _, found := tree.Select(rank); found {
+				t.Errorf("The select function found a node with the rank %d out of range.", rank)
+			}
+		}
+	})
+
+	t.Run("Test rank and select parallel", func(t *testing.T) {
+		/* Goroutines insert all the keys and then remove the odd ones, so that many removals
+		take the two-children path: afterwards the subtree sizes must be exact. */
+		const nodesAmount = 2000
+		const gorutinesAmount = 8
+		tree := orderStatistic(t)
+		order := rand.Perm(nodesAmount)
+		wg := sync.WaitGroup{}
+		wg.Add(gorutinesAmount)
+		for g := 0; g < gorutinesAmount; g++ {
+			go func(g int) {
+				defer wg.Done()
+				for j := g; j < nodesAmount; j += gorutinesAmount {
+					tree.Insert(key(order[j]), value(order[j]))
+				}
+				for j := g; j < nodesAmount; j += gorutinesAmount {
+					if order[j]%2 == 1 {
+						tree.Remove(key(order[j]))
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		if !tree.IsValid() {
+			t.Fatalf("Error: tree is not valid.")
+		}
+		for rank := 0; rank < nodesAmount/2; rank++ {
+			if tree.Rank(key(2*rank)) != rank {
+				t.Fatalf("Received rank %d != expected rank %d", tree.Rank(key(2*rank)), rank)
+			}
+			if k, _, found := tree.Select(rank); !found || k != key(2*rank) {
+				t.Fatalf("The select function found the key %v for the rank %d when the key %d was expected.", k, rank, 2*rank)
+			}
+		}
+	})
+}
+
+const benchmarkNodesAmount = 100_000
+const benchmarkGorutinesAmount = 8
+const benchmarkDeepNodesAmount = 2000
+
+func Benchmark[T comparable, K cmp.Ordered](b *testing.B, factory Factory[T, K]) {
+	// Metrics are measured for sequential and parallel operations on random keys.
+	key, value := factory.Key, factory.Value
+	order := rand.Perm(benchmarkNodesAmount)
+
+	filled := func() trees.BinarySearchTree[T, K] {
+		tree := factory.New()
+		for _, i := range order {
+			tree.Insert(key(i), value(i))
+		}
+		return tree
+	}
+
+	b.Run("Insert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			filled()
+		}
+	})
+
+	b.Run("Find", func(b *testing.B) {
+		tree := filled()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, j := range order {
+				tree.Find(key(j))
+			}
+		}
+	})
+
+	b.Run("Insert and remove", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree := filled()
+			for _, j := range order {
+				tree.Remove(key(j))
+			}
+		}
+	})
+
+	b.Run("Insert | 8 gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree := factory.New()
+			wg := sync.WaitGroup{}
+			wg.Add(benchmarkGorutinesAmount)
+			for g := 0; g < benchmarkGorutinesAmount; g++ {
+				go func() {
+					defer wg.Done()
+					for j := g; j < benchmarkNodesAmount; j += benchmarkGorutinesAmount {
+						tree.Insert(key(order[j]), value(order[j]))
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Find | 8 gorutines", func(b *testing.B) {
+		tree := filled()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			wg := sync.WaitGroup{}
+			wg.Add(benchmarkGorutinesAmount)
+			for g := 0; g < benchmarkGorutinesAmount; g++ {
+				go func() {
+					defer wg.Done()
+					for j := g; j < benchmarkNodesAmount; j += benchmarkGorutinesAmount {
+						tree.Find(key(order[j]))
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Find, insert and remove in random order | 8 gorutines", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree := factory.New()
+			wg := sync.WaitGroup{}
+			wg.Add(benchmarkGorutinesAmount)
+			for g := 0; g < benchmarkGorutinesAmount; g++ {
+				go func() {
+					defer wg.Done()
+					for j := g; j < benchmarkNodesAmount; j += benchmarkGorutinesAmount {
+						switch rand.Intn(3) {
+						case 0:
+							tree.Insert(key(order[j]), value(order[j]))
+						case 1:
+							tree.Remove(key(order[j/2]))
+						default:
+							tree.Find(key(order[j/2]))
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Find in a deep tree | 8 gorutines", func(b *testing.B) {
+		// Ascending insertions turn an unbalanced tree into a path, so every search is long.
+		tree := factory.New()
+		for i := 0; i < benchmarkDeepNodesAmount; i++ {
+			tree.Insert(key(i), value(i))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			wg := sync.WaitGroup{}
+			wg.Add(benchmarkGorutinesAmount)
+			for g := 0; g < benchmarkGorutinesAmount; g++ {
+				go func() {
+					defer wg.Done()
+					for j := g; j < benchmarkDeepNodesAmount; j += benchmarkGorutinesAmount {
+						tree.Find(key(j))
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("All in a deep tree", func(b *testing.B) {
+		// A walk that searched every next key from the root would pay for a whole path on every step here.
+		tree := factory.New()
+		for i := 0; i < benchmarkDeepNodesAmount; i++ {
+			tree.Insert(key(i), value(i))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for range tree.All() {
+			}
+		}
+	})
+
+	for _, reads := range []int{50, 90, 99} {
+		// Mixed workloads on a filled tree, the writes insert and remove the same keys as the finds.
+		b.Run(fmt.Sprintf("%d%% finds, insert and remove | 8 gorutines", reads), func(b *testing.B) {
+			tree := filled()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				wg := sync.WaitGroup{}
+				wg.Add(benchmarkGorutinesAmount)
+				for g := 0; g < benchmarkGorutinesAmount; g++ {
+					go func() {
+						defer wg.Done()
+						for j := g; j < benchmarkNodesAmount; j += benchmarkGorutinesAmount {
+							if operation := rand.Intn(100); operation < reads {
+								tree.Find(key(order[j]))
+							} else if operation%2 == 0 {
+								tree.Insert(key(order[j]), value(order[j]))
+							} else {
+								tree.Remove(key(order[j]))
+							}
+						}
+					}()
+				}
+				wg.Wait()
+			}
+		})
+	}
+}